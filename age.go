@@ -0,0 +1,248 @@
+// ABOUTME: Age and birthday calculations derived from the birth date in a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // Europe/Bucharest must resolve even without a system tz database.
+)
+
+// ErrBirthDateMismatch is returned when a CNP's birth date differs from the one expected.
+var ErrBirthDateMismatch = errors.New("CNP birth date does not match expected birth date")
+
+// bucharest is the default location for "today" when the caller supplies no time.
+// The embedded time/tzdata guarantees it loads, so there is no UTC fallback.
+var bucharest = mustLoadLocation("Europe/Bucharest")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic("rossn: " + err.Error())
+	}
+	return loc
+}
+
+// Age returns the number of full years completed by the CNP holder today,
+// where "today" is the current date in Europe/Bucharest; for today in UTC, use
+// AgeAt(cnp, time.Now().UTC()). Returns an error if the CNP is invalid.
+func Age(cnp string) (int, error) {
+	return AgeAt(cnp, time.Now().In(bucharest))
+}
+
+// Age is like the package-level Age under the Validator's options, with
+// "today" read from its Clock (see WithClock) in Europe/Bucharest.
+func (v *Validator) Age(cnp string) (int, error) {
+	var c CNP
+	if err := v.cfg.decode(cnp, &c); err != nil {
+		return 0, err
+	}
+	y, m, d := c.BirthDate.Date()
+	return ageOn(y, int(m), d, v.cfg.clock.Now().In(bucharest)), nil
+}
+
+// AgeAt returns the number of full years completed by the CNP holder on the
+// calendar date of at, taken in at's own location. A birthday later in the year
+// than at is not yet counted. Returns an error if the CNP is invalid.
+func AgeAt(cnp string, at time.Time) (int, error) {
+	if err := Validate(cnp); err != nil {
+		return 0, err
+	}
+	by, bm, bd := cnpBirthDate(cnp)
+	return ageOn(by, bm, bd, at), nil
+}
+
+// ageOn returns the full years completed on the calendar date of at by someone
+// born on the given date.
+func ageOn(year, month, day int, at time.Time) int {
+	y, m, d := at.Date()
+	age := y - year
+	if int(m) < month || (int(m) == month && d < day) {
+		age--
+	}
+	return age
+}
+
+// DaysUntilBirthday returns the number of days from today, in Europe/Bucharest,
+// until the CNP holder's next birthday (0 if it is today).
+// Returns an error if the CNP is invalid.
+func DaysUntilBirthday(cnp string) (int, error) {
+	return DaysUntilBirthdayAt(cnp, time.Now().In(bucharest))
+}
+
+// DaysUntilBirthday is like the package-level DaysUntilBirthday under the
+// Validator's options, with "today" read from its Clock (see WithClock).
+func (v *Validator) DaysUntilBirthday(cnp string) (int, error) {
+	var c CNP
+	if err := v.cfg.decode(cnp, &c); err != nil {
+		return 0, err
+	}
+	_, m, d := c.BirthDate.Date()
+	return daysUntilBirthday(int(m), d, v.cfg.clock.Now().In(bucharest)), nil
+}
+
+// DaysUntilBirthdayAt returns the number of days from the calendar date of at,
+// taken in at's own location, until the next birthday (0 if it is that day).
+// A 29 February birthday falls on 1 March in non-leap years.
+// Returns an error if the CNP is invalid.
+func DaysUntilBirthdayAt(cnp string, at time.Time) (int, error) {
+	if err := Validate(cnp); err != nil {
+		return 0, err
+	}
+	_, bm, bd := cnpBirthDate(cnp)
+	return daysUntilBirthday(bm, bd, at), nil
+}
+
+// daysUntilBirthday returns the days from the calendar date of at until the
+// next anniversary of the given birth month and day (0 if it is that day).
+func daysUntilBirthday(month, day int, at time.Time) int {
+	y, m, d := at.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	next := time.Date(y, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if next.Before(today) {
+		next = time.Date(y+1, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	return daysBetween(today, next)
+}
+
+// BirthYear returns the full four-digit birth year, with the century taken from
+// the S digit. Returns an error if the CNP is invalid.
+func BirthYear(cnp string) (int, error) {
+	if err := Validate(cnp); err != nil {
+		return 0, err
+	}
+	year, _, _ := cnpBirthDate(cnp)
+	return year, nil
+}
+
+// BirthDate returns the century-aware birth date at midnight UTC, the same
+// value as CNP.BirthDate from Parse. Returns an error if the CNP is invalid.
+func BirthDate(cnp string) (time.Time, error) {
+	return BirthDateIn(cnp, time.UTC)
+}
+
+// BirthDateString returns the century-aware birth date formatted as
+// "2006-01-02", the form Fields reports under FieldBirthDate, for callers that
+// need it as a string for JSON or SQL. Returns an error if the CNP is invalid.
+func BirthDateString(cnp string) (string, error) {
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return formatBirthDate(cnp), nil
+}
+
+// BirthDateIn returns midnight at the start of the birth date in loc, or in
+// Europe/Bucharest if loc is nil, for age and birthday arithmetic done in local
+// time, where the UTC midnight of CNP.BirthDate can fall on another calendar day.
+// Returns an error if the CNP is invalid.
+func BirthDateIn(cnp string, loc *time.Location) (time.Time, error) {
+	if err := Validate(cnp); err != nil {
+		return time.Time{}, err
+	}
+	if loc == nil {
+		loc = bucharest
+	}
+	year, month, day := cnpBirthDate(cnp)
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc), nil
+}
+
+// formatBirthDate formats the birth date of a valid CNP as YYYY-MM-DD.
+func formatBirthDate(cnp string) string {
+	year, month, day := cnpBirthDate(cnp)
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+}
+
+// BirthDecade returns the first year of the decade the holder was born in, so
+// births in 1980–1989 all report 1980. Returns an error if the CNP is invalid.
+func BirthDecade(cnp string) (int, error) {
+	year, err := BirthYear(cnp)
+	if err != nil {
+		return 0, err
+	}
+	return year / 10 * 10, nil
+}
+
+// ValidateExpectingBirthDate validates the CNP and checks that its century-aware
+// birth date equals the calendar date of birth, taken in birth's own location
+// and ignoring the time of day, returning ErrBirthDateMismatch otherwise.
+func ValidateExpectingBirthDate(cnp string, birth time.Time) error {
+	c, err := Parse(cnp)
+	if err != nil {
+		return err
+	}
+	if !c.BirthDate.Equal(calendarDate(birth)) {
+		return ErrBirthDateMismatch
+	}
+	return nil
+}
+
+// Older reports whether the holder of CNP a was born strictly before the holder of b.
+// Birth dates are compared with the century taken from the S digit, so an S=3
+// (18xx) CNP is older than any 19xx CNP regardless of its two-digit year.
+// Returns an error if either CNP is invalid.
+func Older(a, b string) (bool, error) {
+	ka, err := birthKey(a)
+	if err != nil {
+		return false, err
+	}
+	kb, err := birthKey(b)
+	if err != nil {
+		return false, err
+	}
+	return ka < kb, nil
+}
+
+// ByAge implements sort.Interface, ordering CNPs from oldest to youngest.
+// It sorts the slice passed to NewByAge in place.
+type ByAge struct {
+	cnps []string
+	keys []int
+}
+
+// NewByAge validates every CNP and returns a sort adapter over cnps.
+// Returns an error naming the index of the first invalid CNP.
+func NewByAge(cnps []string) (*ByAge, error) {
+	keys := make([]int, len(cnps))
+	for i, cnp := range cnps {
+		k, err := birthKey(cnp)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		keys[i] = k
+	}
+	return &ByAge{cnps: cnps, keys: keys}, nil
+}
+
+func (b *ByAge) Len() int           { return len(b.cnps) }
+func (b *ByAge) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b *ByAge) Swap(i, j int) {
+	b.cnps[i], b.cnps[j] = b.cnps[j], b.cnps[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// SortKey returns a 14-character key whose lexicographic order sorts CNPs by
+// century-aware birth date, for storing in an indexed column that supports
+// birth-date range scans. The layout is fixed: the four-digit birth year, the
+// month and day (YYYYMMDD), then the S digit, the county JJ and the serial NNN.
+// Within a birth date, S places citizens before foreign residents (7, 8) and
+// non-residents (9), with males before females in each group. Keys of distinct
+// CNPs are distinct. Returns an error if the CNP is invalid.
+func SortKey(cnp string) (string, error) {
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	year, _, _ := cnpBirthDate(cnp)
+	f := fields(cnp)
+	return fmt.Sprintf("%04d%s%c%s%s", year, cnp[offMM:offJJ], f.s, f.jj, f.nnn), nil
+}
+
+// birthKey validates the CNP and returns its birth date as a YYYYMMDD integer.
+func birthKey(cnp string) (int, error) {
+	if err := Validate(cnp); err != nil {
+		return 0, err
+	}
+	y, m, d := cnpBirthDate(cnp)
+	return y*10000 + m*100 + d, nil
+}
@@ -0,0 +1,339 @@
+// ABOUTME: Tests for age and birthday helpers, including time-zone boundaries.
+package rossn
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestAgeAt(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		at   time.Time
+		want int
+	}{
+		{buildCNP("1", "90", "12", "31", "01", "001"), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 33},
+		{buildCNP("1", "90", "12", "31", "01", "001"), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 34},
+		{buildCNP("2", "80", "06", "15", "12", "123"), time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), 43},
+		{buildCNP("2", "80", "06", "15", "12", "123"), time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), 44},
+		{buildCNP("3", "99", "01", "01", "02", "321"), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), 100},
+		{buildCNP("6", "04", "02", "29", "52", "456"), time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), 18},
+		{buildCNP("6", "04", "02", "29", "52", "456"), time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), 19},
+	}
+	for _, tc := range cases {
+		got, err := AgeAt(tc.cnp, tc.at)
+		if err != nil {
+			t.Errorf("AgeAt(%s) returned error: %v", tc.cnp, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("AgeAt(%s, %s) = %d, want %d", tc.cnp, tc.at.Format("2006-01-02"), got, tc.want)
+		}
+	}
+
+	if _, err := AgeAt("1800101011234", time.Now()); err == nil {
+		t.Errorf("AgeAt should fail for an invalid CNP")
+	}
+	if _, err := Age(Generate()); err != nil {
+		t.Errorf("Age should succeed for a valid CNP: %v", err)
+	}
+}
+
+func TestDaysUntilBirthdayAt(t *testing.T) {
+	cnp := buildCNP("1", "90", "06", "15", "01", "001")
+	cases := []struct {
+		at   time.Time
+		want int
+	}{
+		{time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC), 0},
+		{time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC), 364},
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 166},
+	}
+	for _, tc := range cases {
+		got, err := DaysUntilBirthdayAt(cnp, tc.at)
+		if err != nil || got != tc.want {
+			t.Errorf("DaysUntilBirthdayAt(%s) = %d, %v; want %d", tc.at.Format("2006-01-02"), got, err, tc.want)
+		}
+	}
+
+	leap := buildCNP("6", "04", "02", "29", "52", "456")
+	if got, _ := DaysUntilBirthdayAt(leap, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)); got != 1 {
+		t.Errorf("Feb 29 birthday should fall on Mar 1 in 2023, got %d days from Feb 28", got)
+	}
+	if got, _ := DaysUntilBirthdayAt(leap, time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)); got != 1 {
+		t.Errorf("Feb 29 birthday should fall on Feb 29 in 2024, got %d days from Feb 28", got)
+	}
+	if _, err := DaysUntilBirthday("1800101011234"); err == nil {
+		t.Errorf("DaysUntilBirthday should fail for an invalid CNP")
+	}
+}
+
+func TestAge_BucharestMidnightBoundary(t *testing.T) {
+	if bucharest.String() != "Europe/Bucharest" {
+		t.Fatalf("bucharest = %v, want Europe/Bucharest", bucharest)
+	}
+	cnp := buildCNP("1", "90", "06", "15", "01", "001")
+	// 22:30 UTC on June 14 is already 01:30 on June 15 in Bucharest (UTC+3).
+	utc := time.Date(2024, 6, 14, 22, 30, 0, 0, time.UTC)
+
+	if age, _ := AgeAt(cnp, utc); age != 33 {
+		t.Errorf("In UTC the birthday has not happened yet, got age %d", age)
+	}
+	if age, _ := AgeAt(cnp, utc.In(bucharest)); age != 34 {
+		t.Errorf("In Bucharest the birthday is today, got age %d", age)
+	}
+	if days, _ := DaysUntilBirthdayAt(cnp, utc); days != 1 {
+		t.Errorf("In UTC the birthday is tomorrow, got %d days", days)
+	}
+	if days, _ := DaysUntilBirthdayAt(cnp, utc.In(bucharest)); days != 0 {
+		t.Errorf("In Bucharest the birthday is today, got %d days", days)
+	}
+}
+
+func TestBirthYearAndDecade(t *testing.T) {
+	cases := []struct {
+		cnp    string
+		year   int
+		decade int
+	}{
+		{buildCNP("1", "80", "01", "01", "01", "001"), 1980, 1980},
+		{buildCNP("2", "89", "12", "31", "01", "001"), 1989, 1980},
+		{buildCNP("3", "99", "06", "15", "01", "001"), 1899, 1890},
+		{buildCNP("6", "00", "02", "29", "01", "001"), 2000, 2000},
+		{buildCNP("9", "05", "06", "15", "70", "001"), 1905, 1900},
+	}
+	for _, tc := range cases {
+		year, err := BirthYear(tc.cnp)
+		if err != nil || year != tc.year {
+			t.Errorf("BirthYear(%s) = %d, %v; want %d", tc.cnp, year, err, tc.year)
+		}
+		decade, err := BirthDecade(tc.cnp)
+		if err != nil || decade != tc.decade {
+			t.Errorf("BirthDecade(%s) = %d, %v; want %d", tc.cnp, decade, err, tc.decade)
+		}
+	}
+	if _, err := BirthYear("123"); err == nil {
+		t.Errorf("BirthYear should fail for an invalid CNP")
+	}
+	if _, err := BirthDecade("123"); err == nil {
+		t.Errorf("BirthDecade should fail for an invalid CNP")
+	}
+}
+
+func TestValidateExpectingBirthDate(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	cases := []struct {
+		birth time.Time
+		want  error
+	}{
+		{time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), nil},
+		{time.Date(1985, 3, 17, 23, 59, 0, 0, time.UTC), nil},
+		{time.Date(1985, 3, 17, 0, 30, 0, 0, bucharest), nil},
+		{time.Date(1985, 3, 18, 0, 0, 0, 0, time.UTC), ErrBirthDateMismatch},
+		{time.Date(2085, 3, 17, 0, 0, 0, 0, time.UTC), ErrBirthDateMismatch},
+		{time.Date(1885, 3, 17, 0, 0, 0, 0, time.UTC), ErrBirthDateMismatch},
+	}
+	for _, tc := range cases {
+		if err := ValidateExpectingBirthDate(cnp, tc.birth); !errors.Is(err, tc.want) {
+			t.Errorf("ValidateExpectingBirthDate(%s, %s) = %v, want %v", cnp, tc.birth, err, tc.want)
+		}
+	}
+	if err := ValidateExpectingBirthDate(buildCNP("3", "85", "03", "17", "40", "123"), time.Date(1885, 3, 17, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("An S=3 CNP should match its 18xx date, got %v", err)
+	}
+	if err := ValidateExpectingBirthDate("123", time.Now()); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Invalid CNP should fail validation first, got %v", err)
+	}
+}
+
+func TestOlder(t *testing.T) {
+	born1880 := buildCNP("3", "80", "06", "15", "02", "321") // 1880, larger YY than 1950
+	born1950 := buildCNP("1", "50", "01", "01", "01", "001")
+	born1999 := buildCNP("2", "99", "12", "31", "12", "123")
+	born2001 := buildCNP("5", "01", "01", "01", "30", "101") // 2001, smaller YY than 1950
+
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{born1880, born1950, true},
+		{born1950, born1880, false},
+		{born1950, born1999, true},
+		{born1999, born2001, true},
+		{born2001, born1950, false},
+		{born1950, born1950, false},
+	}
+	for _, tc := range cases {
+		got, err := Older(tc.a, tc.b)
+		if err != nil || got != tc.want {
+			t.Errorf("Older(%s, %s) = %v, %v; want %v", tc.a, tc.b, got, err, tc.want)
+		}
+	}
+
+	if _, err := Older("123", born1950); err == nil {
+		t.Errorf("Older should fail for an invalid first CNP")
+	}
+	if _, err := Older(born1950, "123"); err == nil {
+		t.Errorf("Older should fail for an invalid second CNP")
+	}
+}
+
+func TestByAge(t *testing.T) {
+	born1880 := buildCNP("3", "80", "06", "15", "02", "321")
+	born1950 := buildCNP("1", "50", "01", "01", "01", "001")
+	born1999 := buildCNP("2", "99", "12", "31", "12", "123")
+	born2001 := buildCNP("5", "01", "01", "01", "30", "101")
+
+	cnps := []string{born2001, born1950, born1999, born1880}
+	byAge, err := NewByAge(cnps)
+	if err != nil {
+		t.Fatalf("NewByAge returned error: %v", err)
+	}
+	sort.Sort(byAge)
+	want := []string{born1880, born1950, born1999, born2001}
+	for i := range want {
+		if cnps[i] != want[i] {
+			t.Errorf("Position %d: got %s, want %s", i, cnps[i], want[i])
+		}
+	}
+
+	if _, err := NewByAge([]string{born1950, "123"}); err == nil {
+		t.Errorf("NewByAge should fail when an input is invalid")
+	}
+}
+
+func TestSortKey(t *testing.T) {
+	a := buildCNP("5", "01", "01", "01", "12", "001") // 2001-01-01
+	key, err := SortKey(a)
+	if err != nil || key != "20010101512001" {
+		t.Errorf("SortKey(%s) = %q, %v; want %q", a, key, err, "20010101512001")
+	}
+
+	// Ascending birth date across centuries, then S within a date.
+	ordered := []string{
+		buildCNP("3", "99", "12", "31", "40", "999"), // 1899-12-31
+		buildCNP("1", "00", "01", "01", "40", "001"), // 1900-01-01
+		buildCNP("2", "85", "03", "17", "01", "001"), // 1985-03-17
+		buildCNP("8", "85", "03", "17", "01", "001"),
+		buildCNP("9", "85", "03", "17", "01", "001"),
+		buildCNP("2", "85", "03", "18", "01", "001"), // 1985-03-18
+		buildCNP("6", "00", "01", "01", "01", "001"), // 2000-01-01
+	}
+	var prev string
+	for _, cnp := range ordered {
+		key, err := SortKey(cnp)
+		if err != nil || len(key) != 14 {
+			t.Fatalf("SortKey(%s) = %q, %v; want a 14-character key", cnp, key, err)
+		}
+		if key <= prev {
+			t.Errorf("SortKey(%s) = %q should sort after %q", cnp, key, prev)
+		}
+		prev = key
+	}
+
+	if _, err := SortKey("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("SortKey of an invalid CNP = %v, want ErrInvalidLength", err)
+	}
+}
+
+func TestBirthDateString(t *testing.T) {
+	cases := []struct{ cnp, want string }{
+		{buildCNP("1", "85", "03", "07", "40", "123"), "1985-03-07"},
+		{buildCNP("4", "99", "12", "31", "40", "123"), "1899-12-31"},
+		{buildCNP("6", "04", "02", "29", "12", "001"), "2004-02-29"},
+		{buildCNP("9", "00", "01", "01", "70", "001"), "1900-01-01"},
+	}
+	for _, tc := range cases {
+		got, err := BirthDateString(tc.cnp)
+		if err != nil || got != tc.want {
+			t.Errorf("BirthDateString(%s) = %q, %v; want %q", tc.cnp, got, err, tc.want)
+		}
+		if f, _ := Fields(tc.cnp); f[FieldBirthDate] != got {
+			t.Errorf("BirthDateString(%s) = %q disagrees with Fields %q", tc.cnp, got, f[FieldBirthDate])
+		}
+	}
+	if got, err := BirthDateString(buildCNP("1", "85", "02", "29", "40", "123")); err == nil || got != "" {
+		t.Errorf("BirthDateString of an invalid CNP = %q, %v; want an error", got, err)
+	}
+}
+
+func TestBirthDateIn(t *testing.T) {
+	cnp := buildCNP("1", "85", "03", "17", "40", "123")
+	tokyo := time.FixedZone("UTC+9", 9*3600)
+	for _, loc := range []*time.Location{time.UTC, tokyo, bucharest} {
+		got, err := BirthDateIn(cnp, loc)
+		y, m, d := got.Date()
+		if err != nil || got.Location() != loc || y != 1985 || m != time.March || d != 17 || got.Hour() != 0 {
+			t.Errorf("BirthDateIn(%s, %s) = %v, %v; want 1985-03-17 00:00 in that location", cnp, loc, got, err)
+		}
+	}
+	if got, _ := BirthDateIn(cnp, nil); got.Location() != bucharest {
+		t.Errorf("A nil location should default to Europe/Bucharest, got %v", got.Location())
+	}
+	// Midnight in UTC+9 is still the previous day in UTC.
+	if got, _ := BirthDateIn(cnp, tokyo); got.UTC().Day() != 16 {
+		t.Errorf("BirthDateIn in UTC+9 should be 16 March in UTC, got %v", got.UTC())
+	}
+	if _, err := BirthDateIn("123", time.UTC); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("BirthDateIn of an invalid CNP = %v, want ErrInvalidLength", err)
+	}
+}
+
+func TestBirthDate(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		want time.Time
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{buildCNP("2", "84", "02", "29", "40", "123"), time.Date(1984, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{buildCNP("3", "96", "02", "29", "12", "001"), time.Date(1896, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{buildCNP("4", "00", "01", "01", "12", "001"), time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{buildCNP("6", "00", "02", "29", "12", "001"), time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{buildCNP("9", "99", "12", "31", "70", "999"), time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := BirthDate(tc.cnp)
+		if err != nil || !got.Equal(tc.want) || got.Location() != time.UTC {
+			t.Errorf("BirthDate(%s) = %v, %v; want %v", tc.cnp, got, err, tc.want)
+		}
+		if c, _ := Parse(tc.cnp); !c.BirthDate.Equal(got) {
+			t.Errorf("BirthDate(%s) = %v disagrees with Parse %v", tc.cnp, got, c.BirthDate)
+		}
+	}
+	// 1800 and 1900 are not leap years.
+	for _, cnp := range []string{buildCNP("3", "00", "02", "29", "12", "001"), buildCNP("1", "00", "02", "29", "12", "001")} {
+		if got, err := BirthDate(cnp); !errors.Is(err, ErrInvalidDate) || !got.IsZero() {
+			t.Errorf("BirthDate(%s) = %v, %v; want ErrInvalidDate", cnp, got, err)
+		}
+	}
+}
+
+// fixedClock is a Clock frozen at a single instant.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestValidator_AgeUsesClock(t *testing.T) {
+	cnp := buildCNP("1", "90", "06", "15", "01", "001")
+	v := NewValidator(WithClock(fixedClock(time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC))))
+
+	if age, err := v.Age(cnp); err != nil || age != 33 {
+		t.Errorf("Age = %d, %v; want 33, nil", age, err)
+	}
+	if days, err := v.DaysUntilBirthday(cnp); err != nil || days != 5 {
+		t.Errorf("DaysUntilBirthday = %d, %v; want 5, nil", days, err)
+	}
+	if _, err := v.Age("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Age of a short CNP: got %v, want ErrInvalidLength", err)
+	}
+}
+
+func TestWithClock_NilFallsBackToSystemClock(t *testing.T) {
+	v := NewValidator(WithClock(nil), SIIEASCMode())
+	if err := v.Validate(buildCNP("1", "90", "06", "15", "01", "001")); err != nil {
+		t.Errorf("Validate with a nil clock: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// ABOUTME: Heuristic, non-fatal flags for CNPs that are valid but look suspicious.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// Flags returned by AnomalyFlags. They are heuristics for triage, not validation failures.
+const (
+	// AnomalyBornBefore1900 marks S=3/4 (born 1800–1899). CNPs were introduced in
+	// 1978, when such holders were at least 79, so a mistyped S is more likely.
+	AnomalyBornBefore1900 = "born_before_1900"
+	// AnomalyForeignArchivalCounty marks S=7/8 with codes 47/48. Foreign-resident
+	// numbers were issued long after the two extra Bucharest sectors were abolished.
+	AnomalyForeignArchivalCounty = "foreign_resident_archival_county"
+	// AnomalyNonResidentSector marks S=9 with a Bucharest sector code (41–46).
+	// Non-resident numbers are normally allocated centrally, not by a sector office.
+	AnomalyNonResidentSector = "non_resident_bucharest_sector"
+	// AnomalyNonResidentDefaultDate marks S=9 with a 1900-01-01 birth date, the
+	// filler date commonly used when a non-resident's real birth date is unknown.
+	AnomalyNonResidentDefaultDate = "non_resident_default_date"
+)
+
+// anomalyRule flags a valid CNP when match reports true for its components,
+// given with the sTable meaning of its S digit.
+type anomalyRule struct {
+	flag  string
+	match func(m sMeaning, f cnpFields, year, month, day int) bool
+}
+
+// anomalyRules is evaluated in order by AnomalyFlags; add new heuristics here.
+var anomalyRules = []anomalyRule{
+	{AnomalyBornBefore1900, func(m sMeaning, _ cnpFields, _, _, _ int) bool {
+		return m.century == 1800
+	}},
+	{AnomalyForeignArchivalCounty, func(m sMeaning, f cnpFields, _, _, _ int) bool {
+		return m.residency == ResidencyForeign && (f.jj == "47" || f.jj == "48")
+	}},
+	{AnomalyNonResidentSector, func(m sMeaning, f cnpFields, _, _, _ int) bool {
+		return m.residency == ResidencyNonResident && f.jj >= "41" && f.jj <= "46"
+	}},
+	{AnomalyNonResidentDefaultDate, func(m sMeaning, _ cnpFields, year, month, day int) bool {
+		return m.residency == ResidencyNonResident && year == 1900 && month == 1 && day == 1
+	}},
+}
+
+// AnomalyFlags returns the heuristic flags raised by a valid CNP, in rule order,
+// or an empty slice if none apply. The flags never affect Validate.
+// Returns an error if the CNP is invalid.
+func AnomalyFlags(cnp string) ([]string, error) {
+	if err := Validate(cnp); err != nil {
+		return nil, err
+	}
+	f := fields(cnp)
+	m, _ := sInfo(f.s)
+	year, month, day := cnpBirthDate(cnp)
+	flags := []string{}
+	for _, r := range anomalyRules {
+		if r.match(m, f, year, month, day) {
+			flags = append(flags, r.flag)
+		}
+	}
+	return flags, nil
+}
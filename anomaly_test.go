@@ -0,0 +1,42 @@
+// ABOUTME: Tests for heuristic anomaly flags on valid CNPs.
+package rossn
+
+import "testing"
+
+func TestAnomalyFlags(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		want []string
+	}{
+		{buildCNP("1", "80", "01", "01", "12", "001"), nil},
+		{buildCNP("3", "80", "06", "15", "02", "321"), []string{AnomalyBornBefore1900}},
+		{buildCNP("7", "75", "03", "12", "47", "111"), []string{AnomalyForeignArchivalCounty}},
+		{buildCNP("9", "90", "01", "01", "43", "555"), []string{AnomalyNonResidentSector}},
+		{buildCNP("9", "00", "01", "01", "70", "555"), []string{AnomalyNonResidentDefaultDate}},
+		{buildCNP("9", "00", "01", "01", "41", "555"), []string{AnomalyNonResidentSector, AnomalyNonResidentDefaultDate}},
+		{buildCNP("9", "90", "01", "01", "40", "555"), nil},
+	}
+	for _, tc := range cases {
+		got, err := AnomalyFlags(tc.cnp)
+		if err != nil {
+			t.Errorf("AnomalyFlags(%s) returned error: %v", tc.cnp, err)
+			continue
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("AnomalyFlags(%s) = %v, want %v", tc.cnp, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("AnomalyFlags(%s) = %v, want %v", tc.cnp, got, tc.want)
+			}
+		}
+		if err := Validate(tc.cnp); err != nil {
+			t.Errorf("Flagged CNP must still validate: %s, err=%v", tc.cnp, err)
+		}
+	}
+
+	if _, err := AnomalyFlags("123"); err == nil {
+		t.Errorf("AnomalyFlags should fail for an invalid CNP")
+	}
+}
@@ -0,0 +1,269 @@
+// ABOUTME: Validation of many CNPs at once, as slices or pipeline channels.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// Result pairs a CNP with the outcome of validating it.
+type Result struct {
+	CNP string
+	Err error // nil if the CNP is valid
+}
+
+// IndexedError describes a CNP that failed at a given index of a batch.
+type IndexedError struct {
+	Index int    // position in the input slice
+	CNP   string // the input value
+	Err   error  // the validation error
+}
+
+// Error implements the error interface.
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList collects every failure of a batch operation, in input order.
+// A nil or empty ErrorList means no failures.
+type ErrorList []IndexedError
+
+// Error summarises the list, reporting the count and the first failure.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	default:
+		return fmt.Sprintf("%d invalid CNPs, first at %v", len(l), l[0])
+	}
+}
+
+// Unwrap returns the individual errors so errors.Is and errors.As can match any of them.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Err returns the list as an error, or nil if it is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// ParseMany parses every CNP with ValidateAndParse, without stopping at failures.
+// The returned slice is parallel to cnps, with nil entries for invalid CNPs, and
+// the ErrorList describes each failure with its index (nil if all are valid).
+func ParseMany(cnps []string) ([]*CNP, ErrorList) {
+	parsed := make([]*CNP, len(cnps))
+	var errs ErrorList
+	for i, cnp := range cnps {
+		c, err := ValidateAndParse(cnp)
+		if err != nil {
+			errs = append(errs, IndexedError{Index: i, CNP: cnp, Err: err})
+			continue
+		}
+		parsed[i] = c
+	}
+	return parsed, errs
+}
+
+// minChunk is the smallest number of CNPs handed to one goroutine by
+// ValidateBatchConcurrent, so that validating a chunk outweighs starting its
+// goroutine; smaller inputs are validated serially. See
+// BenchmarkValidateBatchConcurrent.
+const minChunk = 512
+
+// ValidateBatch validates each CNP in order and returns a slice of errors
+// parallel to cnps, with nil entries for valid CNPs.
+func ValidateBatch(cnps []string) []error {
+	errs := make([]error, len(cnps))
+	for i, cnp := range cnps {
+		errs[i] = Validate(cnp)
+	}
+	return errs
+}
+
+// ValidateBatchGrouped validates each CNP and groups the inputs by outcome, for
+// import reports that count failures by reason. Valid CNPs are under the nil key
+// and each invalid one under the sentinel error it matches with errors.Is, such
+// as ErrInvalidControlDigit; an error matching no sentinel is its own key.
+// Within a group, CNPs keep their input order, duplicates included.
+func ValidateBatchGrouped(cnps []string) map[error][]string {
+	groups := make(map[error][]string)
+	for _, cnp := range cnps {
+		key := rootSentinel(Validate(cnp))
+		groups[key] = append(groups[key], cnp)
+	}
+	return groups
+}
+
+// sentinels lists every sentinel error validation can report, in check order.
+var sentinels = [...]error{
+	ErrInvalidLength, ErrNonDigit, ErrPlaceholder, ErrInvalidGenderDigit,
+	ErrInvalidDate, ErrInvalidCounty, ErrInvalidSerial, ErrInvalidControlDigit,
+	ErrBlocked, ErrSerialRejected, ErrSerialTooHigh, ErrBirthYearTooEarly,
+	ErrBirthYearOutOfRange, ErrSuspiciousDate,
+}
+
+// rootSentinel returns the first sentinel that err matches, or err itself.
+func rootSentinel(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, s := range sentinels {
+		if errors.Is(err, s) {
+			return s
+		}
+	}
+	return err
+}
+
+// ValidateBatchConcurrent is like ValidateBatch but splits cnps into contiguous
+// chunks validated by up to workers goroutines; workers <= 0 uses GOMAXPROCS.
+// Each goroutine receives at least minChunk CNPs, so small inputs are validated
+// serially on the calling goroutine.
+func ValidateBatchConcurrent(cnps []string, workers int) []error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if max := len(cnps) / minChunk; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		return ValidateBatch(cnps)
+	}
+	errs := make([]error, len(cnps))
+	chunk := (len(cnps) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(cnps); start += chunk {
+		end := start + chunk
+		if end > len(cnps) {
+			end = len(cnps)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				errs[i] = Validate(cnps[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return errs
+}
+
+// Bitset records one yes/no outcome per input of a batch, packed 64 to a word,
+// so results for millions of CNPs take an eighth of a byte each.
+type Bitset struct {
+	words []uint64
+	n     int
+}
+
+// Len returns the number of entries in the set.
+func (b *Bitset) Len() int { return b.n }
+
+// IsSet reports whether bit i is set. It returns false for i outside [0, Len()).
+func (b *Bitset) IsSet(i int) bool {
+	if i < 0 || i >= b.n {
+		return false
+	}
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Count returns the number of set bits.
+func (b *Bitset) Count() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// ValidateBitset validates each CNP and returns a Bitset in which bit i is set
+// iff cnps[i] passes Validate, for callers that need only which rows passed.
+func ValidateBitset(cnps []string) *Bitset {
+	return ValidateBitsetConcurrent(cnps, 1)
+}
+
+// ValidateBitsetConcurrent is like ValidateBitset but, as ValidateBatchConcurrent,
+// splits cnps across up to workers goroutines; workers <= 0 uses GOMAXPROCS.
+// Chunks are whole multiples of 64 CNPs, so no two goroutines share a word.
+func ValidateBitsetConcurrent(cnps []string, workers int) *Bitset {
+	b := &Bitset{words: make([]uint64, (len(cnps)+63)/64), n: len(cnps)}
+	fill := func(start, end int) {
+		for i := start; i < end; i++ {
+			if Validate(cnps[i]) == nil {
+				b.words[i/64] |= 1 << (i % 64)
+			}
+		}
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if max := len(cnps) / minChunk; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		fill(0, len(cnps))
+		return b
+	}
+	chunk := ((len(cnps)+workers-1)/workers + 63) / 64 * 64
+	var wg sync.WaitGroup
+	for start := 0; start < len(cnps); start += chunk {
+		end := min(start+chunk, len(cnps))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fill(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+	return b
+}
+
+// ValidateChan validates each CNP received from in and emits a Result for it on
+// the returned channel, in the same order as the input. The returned channel is
+// closed once in is closed or ctx is done, whichever happens first; after
+// cancellation no further values are read from in or sent.
+func ValidateChan(ctx context.Context, in <-chan string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+		for {
+			var cnp string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case cnp, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Result{CNP: cnp, Err: Validate(cnp)}:
+			}
+		}
+	}()
+	return out
+}
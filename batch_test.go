@@ -0,0 +1,213 @@
+// ABOUTME: Tests for batch and pipeline validation.
+package rossn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValidateChan(t *testing.T) {
+	inputs := []string{
+		buildCNP("1", "80", "01", "01", "01", "001"),
+		"123",
+		buildCNP("2", "95", "12", "15", "12", "123"),
+		"0000000000000",
+	}
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for _, cnp := range inputs {
+			in <- cnp
+		}
+	}()
+
+	var results []Result
+	for r := range ValidateChan(context.Background(), in) {
+		results = append(results, r)
+	}
+	if len(results) != len(inputs) {
+		t.Fatalf("Expected %d results, got %d", len(inputs), len(results))
+	}
+	for i, r := range results {
+		if r.CNP != inputs[i] {
+			t.Errorf("Result %d out of order: got %s, want %s", i, r.CNP, inputs[i])
+		}
+		if (r.Err == nil) != (Validate(inputs[i]) == nil) {
+			t.Errorf("Result %d disagrees with Validate: %v", i, r.Err)
+		}
+	}
+}
+
+func TestValidateChan_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string) // never closed
+	out := ValidateChan(ctx, in)
+
+	in <- Generate()
+	<-out
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Errorf("No results expected after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Output channel was not closed after cancellation")
+	}
+}
+
+func TestValidateBatchConcurrent(t *testing.T) {
+	for _, n := range []int{0, 1, minChunk - 1, minChunk*4 + 7} {
+		cnps := make([]string, n)
+		for i := range cnps {
+			if i%3 == 0 {
+				cnps[i] = "bad"
+			} else {
+				cnps[i] = Generate()
+			}
+		}
+		for _, workers := range []int{-1, 0, 1, 3, 8} {
+			errs := ValidateBatchConcurrent(cnps, workers)
+			if len(errs) != n {
+				t.Fatalf("n=%d workers=%d: got %d results", n, workers, len(errs))
+			}
+			for i, err := range errs {
+				if (err == nil) != (i%3 != 0) {
+					t.Errorf("n=%d workers=%d: result %d = %v", n, workers, i, err)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkValidateBatchConcurrent measures throughput across worker counts and
+// input sizes. To reproduce, run on an otherwise idle machine with
+//
+//	go test -run '^$' -bench ValidateBatchConcurrent -benchtime 20x -count 5 -cpu 8
+//
+// and compare ns/op between worker counts for the same size (e.g. with benchstat).
+// Inputs are generated once, outside the timed region; one third are invalid.
+func BenchmarkValidateBatchConcurrent(b *testing.B) {
+	for _, size := range []int{100, 10_000, 1_000_000} {
+		cnps := make([]string, size)
+		for i := range cnps {
+			cnps[i] = Generate()
+			if i%3 == 0 {
+				cnps[i] = cnps[i][:12] + "x"
+			}
+		}
+		for _, workers := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("size=%d/workers=%d", size, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					ValidateBatchConcurrent(cnps, workers)
+				}
+			})
+		}
+	}
+}
+
+func TestParseMany(t *testing.T) {
+	valid := buildCNP("2", "85", "03", "17", "40", "123")
+	inputs := []string{valid, "123", valid, "0000000000000"}
+	parsed, errs := ParseMany(inputs)
+
+	if len(parsed) != len(inputs) {
+		t.Fatalf("Expected %d results, got %d", len(inputs), len(parsed))
+	}
+	if parsed[0] == nil || parsed[2] == nil || parsed[0].County != "40" {
+		t.Errorf("Valid entries should be parsed: %+v, %+v", parsed[0], parsed[2])
+	}
+	if parsed[1] != nil || parsed[3] != nil {
+		t.Errorf("Invalid entries should be nil")
+	}
+	if len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 3 || errs[1].CNP != "0000000000000" {
+		t.Fatalf("Unexpected error list: %v", errs)
+	}
+	if !errors.Is(errs, ErrInvalidLength) || !errors.Is(errs, ErrPlaceholder) {
+		t.Errorf("ErrorList should match each contained error")
+	}
+	if errs.Err() == nil {
+		t.Errorf("Err() should be non-nil when there are failures")
+	}
+
+	_, errs = ParseMany([]string{valid})
+	if errs != nil || errs.Err() != nil {
+		t.Errorf("All-valid input should yield no errors, got %v", errs)
+	}
+}
+
+func TestValidateBatchGrouped(t *testing.T) {
+	valid := buildCNP("2", "85", "03", "17", "40", "123")
+	badDate := buildCNP("1", "80", "02", "30", "01", "001")
+	badControl := valid[:12] + string('0'+(valid[12]-'0'+1)%10)
+	inputs := []string{valid, "123", badControl, valid, badDate, "12345678901x3", badControl}
+	groups := ValidateBatchGrouped(inputs)
+
+	want := map[error][]string{
+		nil:                    {valid, valid},
+		ErrInvalidLength:       {"123"},
+		ErrNonDigit:            {"12345678901x3"},
+		ErrInvalidDate:         {badDate},
+		ErrInvalidControlDigit: {badControl, badControl},
+	}
+	if len(groups) != len(want) {
+		t.Errorf("ValidateBatchGrouped returned %d groups, want %d: %v", len(groups), len(want), groups)
+	}
+	for key, cnps := range want {
+		if fmt.Sprint(groups[key]) != fmt.Sprint(cnps) {
+			t.Errorf("Group %v = %v, want %v", key, groups[key], cnps)
+		}
+	}
+	if got := ValidateBatchGrouped(nil); len(got) != 0 {
+		t.Errorf("Empty input should yield no groups, got %v", got)
+	}
+
+	// Wrapped errors are keyed by their sentinel; unknown errors by themselves.
+	if got := rootSentinel(fmt.Errorf("row 3: %w", ErrInvalidCounty)); got != ErrInvalidCounty {
+		t.Errorf("rootSentinel of a wrapped error = %v, want ErrInvalidCounty", got)
+	}
+	other := errors.New("custom check")
+	if got := rootSentinel(other); got != other {
+		t.Errorf("rootSentinel of an unknown error = %v, want it unchanged", got)
+	}
+}
+
+func TestValidateBitset(t *testing.T) {
+	valid := buildCNP("2", "85", "03", "17", "40", "123")
+	var cnps []string
+	for i := 0; i < 5000; i++ {
+		if i%3 == 0 || i == 64 || i == 4999 {
+			cnps = append(cnps, valid)
+		} else {
+			cnps = append(cnps, "123")
+		}
+	}
+	for _, b := range []*Bitset{ValidateBitset(cnps), ValidateBitsetConcurrent(cnps, 4), ValidateBitsetConcurrent(cnps, 0)} {
+		if b.Len() != len(cnps) {
+			t.Fatalf("Len = %d, want %d", b.Len(), len(cnps))
+		}
+		count := 0
+		for i, cnp := range cnps {
+			want := Validate(cnp) == nil
+			if b.IsSet(i) != want {
+				t.Errorf("IsSet(%d) = %v, want %v", i, b.IsSet(i), want)
+			}
+			if want {
+				count++
+			}
+		}
+		if b.Count() != count {
+			t.Errorf("Count = %d, want %d", b.Count(), count)
+		}
+		if b.IsSet(-1) || b.IsSet(len(cnps)) {
+			t.Errorf("Out-of-range bits should read as unset")
+		}
+	}
+	if empty := ValidateBitset(nil); empty.Len() != 0 || empty.Count() != 0 || empty.IsSet(0) {
+		t.Errorf("Empty input should yield an empty set")
+	}
+}
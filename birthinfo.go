@@ -0,0 +1,53 @@
+// ABOUTME: Profile-style birth details (date, weekday, age, zodiac) in one call.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "time"
+
+// BirthDetails bundles the birth-related values a profile page typically shows.
+type BirthDetails struct {
+	BirthDate         time.Time    // century-aware birth date at midnight UTC
+	Weekday           time.Weekday // day of the week of the birth date
+	Age               int          // full years completed on the reference date
+	DaysUntilBirthday int          // days until the next birthday, 0 if it is today
+	Zodiac            string       // Western zodiac sign, e.g. "Aries"
+}
+
+// BirthInfo validates the CNP once and returns its birth details relative to
+// the calendar date of at, as computed by AgeAt and DaysUntilBirthdayAt.
+// Returns an error if the CNP is invalid.
+func BirthInfo(cnp string, at time.Time) (BirthDetails, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return BirthDetails{}, err
+	}
+	y, m, d := c.BirthDate.Date()
+	return BirthDetails{
+		BirthDate:         c.BirthDate,
+		Weekday:           c.BirthDate.Weekday(),
+		Age:               ageOn(y, int(m), d, at),
+		DaysUntilBirthday: daysUntilBirthday(int(m), d, at),
+		Zodiac:            zodiacSign(m, d),
+	}, nil
+}
+
+// zodiacStarts gives, for each month, the first day of the sign that begins in
+// it and the sign's name; earlier days belong to the previous month's sign.
+var zodiacStarts = [12]struct {
+	day  int
+	sign string
+}{
+	{20, "Aquarius"}, {19, "Pisces"}, {21, "Aries"}, {20, "Taurus"},
+	{21, "Gemini"}, {21, "Cancer"}, {23, "Leo"}, {23, "Virgo"},
+	{23, "Libra"}, {23, "Scorpio"}, {22, "Sagittarius"}, {22, "Capricorn"},
+}
+
+// zodiacSign returns the Western zodiac sign for a month and day.
+func zodiacSign(m time.Month, d int) string {
+	i := int(m) - 1
+	if d >= zodiacStarts[i].day {
+		return zodiacStarts[i].sign
+	}
+	return zodiacStarts[(i+11)%12].sign
+}
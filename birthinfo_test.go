@@ -0,0 +1,54 @@
+// ABOUTME: Tests for BirthInfo and the zodiac lookup.
+package rossn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBirthInfo(t *testing.T) {
+	cnp := buildCNP("2", "80", "06", "15", "12", "123")
+	at := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	got, err := BirthInfo(cnp, at)
+	if err != nil {
+		t.Fatalf("BirthInfo(%s) returned error: %v", cnp, err)
+	}
+	want := BirthDetails{
+		BirthDate:         time.Date(1980, 6, 15, 0, 0, 0, 0, time.UTC),
+		Weekday:           time.Sunday,
+		Age:               43,
+		DaysUntilBirthday: 5,
+		Zodiac:            "Gemini",
+	}
+	if got != want {
+		t.Errorf("BirthInfo(%s) = %+v, want %+v", cnp, got, want)
+	}
+
+	if _, err := BirthInfo("123", at); err == nil {
+		t.Errorf("BirthInfo should fail for an invalid CNP")
+	}
+}
+
+func TestZodiacSign(t *testing.T) {
+	cases := []struct {
+		m    time.Month
+		d    int
+		want string
+	}{
+		{time.January, 1, "Capricorn"},
+		{time.January, 19, "Capricorn"},
+		{time.January, 20, "Aquarius"},
+		{time.February, 29, "Pisces"},
+		{time.March, 20, "Pisces"},
+		{time.March, 21, "Aries"},
+		{time.July, 22, "Cancer"},
+		{time.July, 23, "Leo"},
+		{time.December, 21, "Sagittarius"},
+		{time.December, 22, "Capricorn"},
+	}
+	for _, tc := range cases {
+		if got := zodiacSign(tc.m, tc.d); got != tc.want {
+			t.Errorf("zodiacSign(%s %d) = %s, want %s", tc.m, tc.d, got, tc.want)
+		}
+	}
+}
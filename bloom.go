@@ -0,0 +1,72 @@
+// ABOUTME: Probabilistic, bounded-memory duplicate detection over CNP streams.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"sync"
+)
+
+// BloomDedupe flags CNPs that were probably seen before, in memory fixed at
+// construction. A CNP reported as new is certainly new; one reported as seen
+// may be a false positive, with probability close to the configured rate as
+// long as no more than the expected number of distinct CNPs is added (and
+// rising beyond it). It is safe for concurrent use.
+type BloomDedupe struct {
+	mu   sync.Mutex
+	bits []uint64
+	m    uint64 // number of bits, a power of two
+	k    int    // number of probes per CNP
+}
+
+// NewBloomDedupe sizes a filter for n distinct CNPs at false-positive rate fp,
+// using -n·ln(fp)/ln²2 bits rounded up to a power of two (2 MB for a million
+// CNPs at 1%), which can only lower the false-positive rate. n is raised to at least 1 and fp is clamped to [1e-12, 0.5].
+func NewBloomDedupe(n int, fp float64) *BloomDedupe {
+	if n < 1 {
+		n = 1
+	}
+	fp = math.Min(math.Max(fp, 1e-12), 0.5)
+	m := uint64(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	m = 1 << bits.Len64(m-1)
+	return &BloomDedupe{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// SeenBefore reports whether cnp was probably passed to SeenBefore earlier, and
+// records it. Invalid CNPs are neither recorded nor reported as seen.
+func (b *BloomDedupe) SeenBefore(cnp string) bool {
+	if Validate(cnp) != nil {
+		return false
+	}
+	v, _ := strconv.ParseUint(cnp, 10, 64)
+	h1, h2 := splitmix64(v), splitmix64(v^0x9e3779b97f4a7c15)
+	h2 |= 1 // odd and m a power of two, so the probes cycle through all bits
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	seen := true
+	for i := 0; i < b.k; i++ {
+		bit := (h1 + uint64(i)*h2) & (b.m - 1)
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			seen = false
+			b.bits[word] |= mask
+		}
+	}
+	return seen
+}
+
+// splitmix64 is the SplitMix64 finaliser, a fast, well-mixed 64-bit hash.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
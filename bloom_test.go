@@ -0,0 +1,64 @@
+// ABOUTME: Tests for Bloom-filter duplicate detection.
+package rossn
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBloomDedupe(t *testing.T) {
+	const n = 20000
+	b := NewBloomDedupe(n, 0.01)
+	if b.m&(b.m-1) != 0 || b.m < 191702 || int(b.m) != len(b.bits)*64 {
+		t.Fatalf("m = %d should be the ideal 191702 bits rounded up to a power of two", b.m)
+	}
+	cnps := make([]string, 0, n+2000)
+	for i := 0; i < n+2000; i++ {
+		cnps = append(cnps, GenerateIndexed(i*7))
+	}
+
+	for _, cnp := range cnps[:n] {
+		b.SeenBefore(cnp)
+	}
+	// No false negatives.
+	for _, cnp := range cnps[:n] {
+		if !b.SeenBefore(cnp) {
+			t.Fatalf("%s was added but reported as new", cnp)
+		}
+	}
+	// False positives near the configured rate. Each probe is recorded, so only
+	// a small sample is used to keep the load close to n.
+	const probes = 2000
+	fp := 0
+	for _, cnp := range cnps[n : n+probes] {
+		if b.SeenBefore(cnp) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / probes; rate > 0.03 {
+		t.Errorf("False-positive rate %.4f is far above 0.01", rate)
+	}
+
+	if b.SeenBefore("123") || b.SeenBefore("123") {
+		t.Errorf("Invalid CNPs should never be reported as seen")
+	}
+}
+
+func TestBloomDedupe_Concurrent(t *testing.T) {
+	b := NewBloomDedupe(0, 2) // clamped to usable parameters
+	cnp := Generate()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.SeenBefore(cnp)
+			}
+		}()
+	}
+	wg.Wait()
+	if !b.SeenBefore(cnp) {
+		t.Errorf("%s should be reported as seen", cnp)
+	}
+}
@@ -0,0 +1,195 @@
+// ABOUTME: Construction of CNP numbers from their individual components.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FromComponents builds a CNP from its numeric components, zero-padding the county
+// to two digits and the serial to three, and appending the control digit.
+// year is the full four-digit birth year and must lie in the century encoded by s.
+// Returns an error if a component is out of range or the result fails Validate
+// (for example an illegal county for that date).
+func FromComponents(s, year, month, day, county, serial int) (string, error) {
+	if s < 1 || s > 9 {
+		return "", fmt.Errorf("%w: S=%d", ErrInvalidGenderDigit, s)
+	}
+	sd := byte('0' + s)
+	if c := sCentury(sd); year < c || year >= c+100 {
+		return "", fmt.Errorf("%w: year %d is not in the %d–%d range encoded by S=%d", ErrInvalidDate, year, c, c+99, s)
+	}
+	if !isCalendarDate(year, month, day) {
+		return "", fmt.Errorf("%w: %04d-%02d-%02d", ErrInvalidDate, year, month, day)
+	}
+	if county < 1 || county > 99 {
+		return "", fmt.Errorf("%w: %d", ErrInvalidCounty, county)
+	}
+	if serial < 1 || serial > 999 {
+		return "", fmt.Errorf("%w: %d", ErrInvalidSerial, serial)
+	}
+	cnp := assemble(sd, year, month, day, county, serial)
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return cnp, nil
+}
+
+// Rebuild returns the canonical CNP string for the struct's current fields, for
+// edit-and-save workflows. The control digit is recomputed and the Control field
+// ignored. The S digit is re-derived from Gender and the year of BirthDate; of
+// the S field only the residency it encodes is kept, so a foreign resident
+// (S=7/8) or non-resident (S=9) born 19xx stays one. An unassigned S, as in a
+// CNP built by hand, counts as a citizen.
+// Returns an error if the components are illegal, as for FromComponents.
+func (c *CNP) Rebuild() (string, error) {
+	year, month, day := c.BirthDate.Date()
+	residency := ResidencyCitizen
+	if m, ok := sInfo(c.S); ok {
+		residency = m.residency
+	}
+	var s byte
+	switch {
+	case c.Gender == "" && residency == ResidencyNonResident && year >= 1900 && year < 2000:
+		s = SNonResident
+	case c.Gender == Male || c.Gender == Female:
+		s = sDigitFor(c.Gender, year, residency != ResidencyCitizen)
+	default:
+		return "", fmt.Errorf("cannot derive S digit for gender %q born %d", c.Gender, year)
+	}
+	if len(c.County) != 2 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCounty, c.County)
+	}
+	county, err := strconv.Atoi(c.County)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCounty, c.County)
+	}
+	return FromComponents(int(s-'0'), year, int(month), day, county, c.Serial)
+}
+
+// Builder assembles a CNP one field at a time, for multi-step forms that need
+// feedback on each field as it is entered. Every setter checks the new value
+// against the fields already set, including the cross-field rules for counties
+// 47, 48 and 70, and leaves the Builder unchanged if it returns an error. The
+// zero value is an empty Builder ready to use.
+type Builder struct {
+	gender    Gender
+	residency ResidencyStatus // "" until set, meaning any
+	birth     time.Time
+	hasBirth  bool
+	county    string
+	serial    int
+}
+
+// SetGender sets the holder's gender, Male or Female. Non-residents (S=9)
+// encode no gender, so it fails once the residency is ResidencyNonResident.
+func (b *Builder) SetGender(g Gender) error {
+	if g != Male && g != Female {
+		return fmt.Errorf("invalid gender %q", g)
+	}
+	next := *b
+	next.gender = g
+	return b.apply(next)
+}
+
+// SetResidency sets the residency status, which selects between the citizen
+// S digits 1–6 and the digits 7–9. Left unset, Build prefers a citizen digit,
+// falling back to 7–9 only where the county requires it, as 70 does before 2024.
+func (b *Builder) SetResidency(r ResidencyStatus) error {
+	if r != ResidencyCitizen && r != ResidencyForeign && r != ResidencyNonResident {
+		return fmt.Errorf("invalid residency status %q", r)
+	}
+	next := *b
+	next.residency = r
+	return b.apply(next)
+}
+
+// SetBirthDate sets the birth date from the calendar date of birth, taken in
+// its own location.
+func (b *Builder) SetBirthDate(birth time.Time) error {
+	next := *b
+	next.birth, next.hasBirth = calendarDate(birth), true
+	return b.apply(next)
+}
+
+// SetCounty sets the two-digit county code, such as "12".
+func (b *Builder) SetCounty(county string) error {
+	if !IsCountyCodeKnown(county) {
+		return fmt.Errorf("%w: %q", ErrInvalidCounty, county)
+	}
+	next := *b
+	next.county = county
+	return b.apply(next)
+}
+
+// SetSerial sets the NNN serial, 1–999.
+func (b *Builder) SetSerial(serial int) error {
+	if serial < 1 || serial > 999 {
+		return fmt.Errorf("%w: %d", ErrInvalidSerial, serial)
+	}
+	b.serial = serial
+	return nil
+}
+
+// Build returns the CNP for the fields set so far, appending the control digit.
+// Gender (unless the holder is a non-resident), birth date, county and serial
+// must all be set. Returns an error if a field is missing or, as a safeguard,
+// if the result fails Validate.
+func (b *Builder) Build() (string, error) {
+	switch {
+	case b.gender == "" && b.residency != ResidencyNonResident:
+		return "", errors.New("gender is not set")
+	case !b.hasBirth:
+		return "", errors.New("birth date is not set")
+	case b.county == "":
+		return "", errors.New("county is not set")
+	case b.serial == 0:
+		return "", errors.New("serial is not set")
+	}
+	s := b.sDigits(true)[0] // apply guarantees at least one
+	county, _ := strconv.Atoi(b.county)
+	year, month, day := b.birth.Date()
+	cnp := assemble(s, year, int(month), day, county, b.serial)
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return cnp, nil
+}
+
+// apply replaces the Builder with next if its fields are consistent.
+func (b *Builder) apply(next Builder) error {
+	if y := next.birth.Year(); next.hasBirth && (y < 1800 || y > 2099) {
+		return fmt.Errorf("%w: %s is outside 1800–2099", ErrInvalidDate, next.birth.Format("2006-01-02"))
+	}
+	if len(next.sDigits(false)) == 0 {
+		return fmt.Errorf("%w: no S digit encodes the gender, residency and birth date set", ErrInvalidGenderDigit)
+	}
+	if len(next.sDigits(true)) == 0 {
+		return fmt.Errorf("%w: county %s is not valid for this holder born %s", ErrInvalidCounty, next.county, next.birth.Format("2006-01-02"))
+	}
+	*b = next
+	return nil
+}
+
+// sDigits returns, in ascending order, the S digits consistent with the fields
+// set. With withCounty, it keeps only those for which the county is valid, once
+// both the county and the birth date are known.
+func (b *Builder) sDigits(withCounty bool) []byte {
+	var out []byte
+	for s := SMale1900; s <= SNonResident; s++ {
+		m, _ := sInfo(s)
+		switch {
+		case b.gender != "" && m.gender != b.gender,
+			b.residency != "" && m.residency != b.residency,
+			b.hasBirth && (b.birth.Year() < m.century || b.birth.Year() >= m.century+100),
+			withCounty && b.hasBirth && b.county != "" && !countyAllowed(b.county, s, b.birth):
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,221 @@
+// ABOUTME: Tests for building CNPs from components.
+package rossn
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFromComponents(t *testing.T) {
+	cases := []struct {
+		s, year, month, day, county, serial int
+		want                                string
+	}{
+		{1, 1980, 1, 1, 1, 1, buildCNP("1", "80", "01", "01", "01", "001")},
+		{2, 1995, 12, 15, 12, 42, buildCNP("2", "95", "12", "15", "12", "042")},
+		{3, 1899, 12, 31, 39, 999, buildCNP("3", "99", "12", "31", "39", "999")},
+		{6, 2004, 2, 29, 52, 7, buildCNP("6", "04", "02", "29", "52", "007")},
+	}
+	for _, tc := range cases {
+		got, err := FromComponents(tc.s, tc.year, tc.month, tc.day, tc.county, tc.serial)
+		if err != nil || got != tc.want {
+			t.Errorf("FromComponents(%d, %d, %d, %d, %d, %d) = %s, %v; want %s",
+				tc.s, tc.year, tc.month, tc.day, tc.county, tc.serial, got, err, tc.want)
+		}
+	}
+}
+
+func TestFromComponents_Errors(t *testing.T) {
+	cases := []struct {
+		s, year, month, day, county, serial int
+		want                                error
+	}{
+		{0, 1980, 1, 1, 1, 1, ErrInvalidGenderDigit},
+		{10, 1980, 1, 1, 1, 1, ErrInvalidGenderDigit},
+		{1, 2001, 1, 1, 1, 1, ErrInvalidDate}, // S=1 encodes 19xx
+		{1, 1981, 2, 29, 1, 1, ErrInvalidDate},
+		{1, 1980, 13, 1, 1, 1, ErrInvalidDate},
+		{1, 1980, 1, 1, 0, 1, ErrInvalidCounty},
+		{1, 1980, 1, 1, 100, 1, ErrInvalidCounty},
+		{1, 1980, 1, 1, 49, 1, ErrInvalidCounty},
+		{1, 1985, 1, 1, 47, 1, ErrInvalidCounty}, // 47 after the 1979 cutoff
+		{1, 1980, 1, 1, 1, 0, ErrInvalidSerial},
+		{1, 1980, 1, 1, 1, 1000, ErrInvalidSerial},
+	}
+	for _, tc := range cases {
+		_, err := FromComponents(tc.s, tc.year, tc.month, tc.day, tc.county, tc.serial)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("FromComponents(%d, %d, %d, %d, %d, %d) error = %v, want %v",
+				tc.s, tc.year, tc.month, tc.day, tc.county, tc.serial, err, tc.want)
+		}
+	}
+}
+
+func TestCNP_Rebuild(t *testing.T) {
+	orig := buildCNP("1", "85", "03", "17", "40", "123")
+	c, err := Parse(orig)
+	if err != nil {
+		t.Fatalf("Parse(%s) returned error: %v", orig, err)
+	}
+	if got, err := c.Rebuild(); err != nil || got != orig {
+		t.Errorf("Rebuild of an unedited CNP = %s, %v; want %s", got, err, orig)
+	}
+
+	// Moving the birth date to another century re-derives S and the control digit.
+	c.BirthDate = time.Date(2005, 7, 1, 0, 0, 0, 0, time.UTC)
+	c.Gender = Female
+	if got, err := c.Rebuild(); err != nil || got != buildCNP("6", "05", "07", "01", "40", "123") {
+		t.Errorf("Rebuild after edit = %s, %v", got, err)
+	}
+
+	// Foreign and non-resident status carries over for 19xx births.
+	for _, s := range []string{"7", "8", "9"} {
+		cnp := buildCNP(s, "90", "01", "01", "70", "555")
+		p, _ := Parse(cnp)
+		p.Serial = 556
+		if got, err := p.Rebuild(); err != nil || got != buildCNP(s, "90", "01", "01", "70", "556") {
+			t.Errorf("Rebuild of edited %s = %s, %v", cnp, got, err)
+		}
+	}
+
+	// A hand-built CNP has no S digit and is rebuilt as a citizen.
+	manual := &CNP{Gender: Male, BirthDate: time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), County: "12", Serial: 123}
+	if got, err := manual.Rebuild(); err != nil || got != buildCNP("1", "85", "03", "17", "12", "123") {
+		t.Errorf("Rebuild of a hand-built CNP = %s, %v", got, err)
+	}
+
+	bad := []func(c *CNP){
+		func(c *CNP) { c.County = "49" },
+		func(c *CNP) { c.County = "4" },
+		func(c *CNP) { c.Serial = 0 },
+		func(c *CNP) { c.Gender = "X" },
+		func(c *CNP) { c.Gender = "" }, // a citizen must have a gender
+		func(c *CNP) { c.BirthDate = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	for i, edit := range bad {
+		c, _ := Parse(orig)
+		edit(c)
+		if got, err := c.Rebuild(); err == nil {
+			t.Errorf("Edit %d should make Rebuild fail, got %s", i, got)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	var b Builder
+	if err := b.SetGender(Female); err != nil {
+		t.Fatalf("SetGender: %v", err)
+	}
+	if err := b.SetBirthDate(date(1985, 3, 17)); err != nil {
+		t.Fatalf("SetBirthDate: %v", err)
+	}
+	if err := b.SetCounty("40"); err != nil {
+		t.Fatalf("SetCounty: %v", err)
+	}
+	if err := b.SetSerial(123); err != nil {
+		t.Fatalf("SetSerial: %v", err)
+	}
+	if got, err := b.Build(); err != nil || got != buildCNP("2", "85", "03", "17", "40", "123") {
+		t.Errorf("Build = %s, %v", got, err)
+	}
+
+	// Cross-field rules are enforced whichever field is set first, and a
+	// rejected value leaves the Builder unchanged.
+	b = Builder{}
+	_ = b.SetBirthDate(date(1990, 5, 1))
+	if err := b.SetCounty("47"); !errors.Is(err, ErrInvalidCounty) {
+		t.Errorf("SetCounty(47) after a 1990 birth date = %v, want ErrInvalidCounty", err)
+	}
+	b = Builder{}
+	_ = b.SetCounty("48")
+	if err := b.SetBirthDate(date(1990, 5, 1)); !errors.Is(err, ErrInvalidCounty) {
+		t.Errorf("SetBirthDate(1990) after county 48 = %v, want ErrInvalidCounty", err)
+	}
+	if err := b.SetBirthDate(date(1975, 5, 1)); err != nil {
+		t.Errorf("SetBirthDate(1975) after county 48 = %v, want nil", err)
+	}
+
+	// County 70 before 2024 needs S=7–9: a citizen cannot have it.
+	b = Builder{}
+	_ = b.SetResidency(ResidencyCitizen)
+	_ = b.SetBirthDate(date(1985, 3, 17))
+	if err := b.SetCounty("70"); !errors.Is(err, ErrInvalidCounty) {
+		t.Errorf("SetCounty(70) for a 1985 citizen = %v, want ErrInvalidCounty", err)
+	}
+	if err := b.SetBirthDate(date(2024, 3, 17)); err != nil {
+		t.Fatalf("SetBirthDate(2024): %v", err)
+	}
+	if err := b.SetCounty("70"); err != nil {
+		t.Errorf("SetCounty(70) for a 2024 citizen = %v, want nil", err)
+	}
+	_ = b.SetGender(Male)
+	_ = b.SetSerial(1)
+	if got, err := b.Build(); err != nil || got != buildCNP("5", "24", "03", "17", "70", "001") {
+		t.Errorf("Build = %s, %v", got, err)
+	}
+
+	// With the residency unset, county 70 before 2024 selects a foreign-resident digit.
+	b = Builder{}
+	_ = b.SetGender(Male)
+	_ = b.SetBirthDate(date(1985, 3, 17))
+	_ = b.SetCounty("70")
+	_ = b.SetSerial(5)
+	if got, err := b.Build(); err != nil || got != buildCNP("7", "85", "03", "17", "70", "005") {
+		t.Errorf("Build = %s, %v", got, err)
+	}
+
+	// Non-residents have no gender and S=9 only encodes 19xx.
+	b = Builder{}
+	_ = b.SetResidency(ResidencyNonResident)
+	if err := b.SetGender(Female); !errors.Is(err, ErrInvalidGenderDigit) {
+		t.Errorf("SetGender for a non-resident = %v, want ErrInvalidGenderDigit", err)
+	}
+	if err := b.SetBirthDate(date(2005, 1, 1)); !errors.Is(err, ErrInvalidGenderDigit) {
+		t.Errorf("SetBirthDate(2005) for a non-resident = %v, want ErrInvalidGenderDigit", err)
+	}
+	_ = b.SetBirthDate(date(1985, 3, 17))
+	_ = b.SetCounty("12")
+	_ = b.SetSerial(999)
+	if got, err := b.Build(); err != nil || got != buildCNP("9", "85", "03", "17", "12", "999") {
+		t.Errorf("Build = %s, %v", got, err)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	var b Builder
+	if err := b.SetGender("X"); err == nil {
+		t.Errorf("SetGender should reject unknown genders")
+	}
+	if err := b.SetResidency("tourist"); err == nil {
+		t.Errorf("SetResidency should reject unknown statuses")
+	}
+	if err := b.SetBirthDate(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("SetBirthDate(2100) = %v, want ErrInvalidDate", err)
+	}
+	for _, county := range []string{"49", "7", ""} {
+		if err := b.SetCounty(county); !errors.Is(err, ErrInvalidCounty) {
+			t.Errorf("SetCounty(%q) = %v, want ErrInvalidCounty", county, err)
+		}
+	}
+	for _, serial := range []int{0, 1000} {
+		if err := b.SetSerial(serial); !errors.Is(err, ErrInvalidSerial) {
+			t.Errorf("SetSerial(%d) = %v, want ErrInvalidSerial", serial, err)
+		}
+	}
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build of an empty Builder should fail")
+	}
+
+	// Each required field is reported until it is set.
+	_ = b.SetGender(Male)
+	_ = b.SetBirthDate(time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC))
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build without a county should fail")
+	}
+	_ = b.SetCounty("12")
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build without a serial should fail")
+	}
+}
@@ -0,0 +1,27 @@
+// ABOUTME: Residency status and single-pass classification of a CNP for routing.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// ResidencyStatus is the residency encoded by the S digit of a CNP. Its values
+// match the FieldResidency entry returned by Fields.
+type ResidencyStatus string
+
+// Residency statuses encoded by the S digit.
+const (
+	ResidencyCitizen     ResidencyStatus = "citizen"          // S=1–6
+	ResidencyForeign     ResidencyStatus = "foreign_resident" // S=7–8
+	ResidencyNonResident ResidencyStatus = "non_resident"     // S=9
+)
+
+// ValidateAndClassify validates the CNP and returns its residency status and
+// gender from the same pass, for dispatchers that branch on both. Gender is ""
+// for S=9, which encodes none. Returns empty values and the validation error if
+// the CNP is invalid.
+func ValidateAndClassify(cnp string) (ResidencyStatus, Gender, error) {
+	if err := defaultConfig.check(cnp, nil); err != nil {
+		return "", "", err
+	}
+	m, _ := sInfo(cnp[offS])
+	return m.residency, m.gender, nil
+}
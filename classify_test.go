@@ -0,0 +1,59 @@
+// ABOUTME: Tests for ValidateAndClassify.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAndClassify(t *testing.T) {
+	tests := []struct {
+		cnp       string
+		residency ResidencyStatus
+		gender    Gender
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), ResidencyCitizen, Male},
+		{buildCNP("4", "85", "03", "17", "40", "123"), ResidencyCitizen, Female},
+		{buildCNP("6", "05", "03", "17", "12", "001"), ResidencyCitizen, Female},
+		{buildCNP("7", "85", "03", "17", "70", "123"), ResidencyForeign, Male},
+		{buildCNP("8", "85", "03", "17", "12", "123"), ResidencyForeign, Female},
+		{buildCNP("9", "85", "03", "17", "70", "123"), ResidencyNonResident, ""},
+	}
+	for _, tt := range tests {
+		r, g, err := ValidateAndClassify(tt.cnp)
+		if err != nil || r != tt.residency || g != tt.gender {
+			t.Errorf("ValidateAndClassify(%s) = %q, %q, %v; want %q, %q", tt.cnp, r, g, err, tt.residency, tt.gender)
+		}
+		f, _ := Fields(tt.cnp)
+		if f[FieldResidency] != string(r) {
+			t.Errorf("ValidateAndClassify(%s) residency %q disagrees with Fields %q", tt.cnp, r, f[FieldResidency])
+		}
+	}
+
+	bad := buildCNP("1", "80", "02", "30", "01", "001")
+	r, g, err := ValidateAndClassify(bad)
+	if !errors.Is(err, ErrInvalidDate) || r != "" || g != "" {
+		t.Errorf("ValidateAndClassify(%s) = %q, %q, %v; want ErrInvalidDate and empty values", bad, r, g, err)
+	}
+}
+
+// BenchmarkValidateAndClassify classifies in one pass, while
+// BenchmarkValidateThenClassify validates, then calls GenderOf and looks the
+// residency up from the S digit, the sequence ValidateAndClassify replaces.
+func BenchmarkValidateAndClassify(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		_, _, _ = ValidateAndClassify(cnp)
+	}
+}
+
+func BenchmarkValidateThenClassify(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		if Validate(cnp) == nil {
+			_, _ = GenderOf(cnp)
+			m, _ := sInfo(cnp[offS])
+			_ = m.residency
+		}
+	}
+}
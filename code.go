@@ -0,0 +1,35 @@
+// ABOUTME: Code, a validated CNP string type usable as a map key or struct field.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// Code is a CNP held as a plain string value. It is comparable, so it can be
+// used as a map key, and adds type safety over bare strings. (The name CNP is
+// taken by the decoded struct returned by Parse.)
+type Code string
+
+// NewCode validates s and returns it as a Code.
+// Returns the validation error if s is not a valid CNP.
+func NewCode(s string) (Code, error) {
+	if err := Validate(s); err != nil {
+		return "", err
+	}
+	return Code(s), nil
+}
+
+// Valid validates the code with Validate. Codes not built with NewCode, such
+// as conversions or zero values, may be invalid.
+func (c Code) Valid() error {
+	return Validate(string(c))
+}
+
+// String returns the code unchanged.
+func (c Code) String() string {
+	return string(c)
+}
+
+// LogString returns the code with everything but the S digit replaced by '*',
+// for logs that must not contain the full CNP.
+func (c Code) LogString() string {
+	return mask(string(c))
+}
@@ -0,0 +1,39 @@
+// ABOUTME: Tests for the Code string type.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCode(t *testing.T) {
+	valid := buildCNP("1", "80", "01", "01", "01", "001")
+	c, err := NewCode(valid)
+	if err != nil {
+		t.Fatalf("NewCode(%s) returned error: %v", valid, err)
+	}
+	if c.String() != valid || c.Valid() != nil {
+		t.Errorf("Code should round-trip a valid CNP, got %q", c)
+	}
+	if _, err := NewCode("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("NewCode should reject invalid input, got %v", err)
+	}
+	if err := Code("123").Valid(); err == nil {
+		t.Errorf("Valid should reject an unchecked conversion")
+	}
+
+	seen := map[Code]int{c: 1}
+	if seen[Code(valid)] != 1 {
+		t.Errorf("Code should work as a map key")
+	}
+}
+
+func TestCode_LogString(t *testing.T) {
+	c := Code(buildCNP("2", "95", "12", "15", "12", "123"))
+	if got := c.LogString(); got != "2************" {
+		t.Errorf("LogString() = %q", got)
+	}
+	if got := Code("").LogString(); got != "" {
+		t.Errorf("LogString() of empty code = %q", got)
+	}
+}
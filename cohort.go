@@ -0,0 +1,98 @@
+// ABOUTME: Birth cohorts: generation labels and anonymised cohort keys from the birth date.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "fmt"
+
+// Cohort names a range of birth years, inclusive at both ends.
+type Cohort struct {
+	Label       string
+	First, Last int
+}
+
+// defaultCohorts are the boundaries used by Generation unless WithCohorts
+// replaces them. They follow the widely used Pew Research Center ranges, with
+// Gen Alpha taken as 2013–2024 and Gen Beta as 2025–2039.
+var defaultCohorts = []Cohort{
+	{"Greatest Generation", 1901, 1927},
+	{"Silent Generation", 1928, 1945},
+	{"Boomer", 1946, 1964},
+	{"Gen X", 1965, 1980},
+	{"Millennial", 1981, 1996},
+	{"Gen Z", 1997, 2012},
+	{"Gen Alpha", 2013, 2024},
+	{"Gen Beta", 2025, 2039},
+}
+
+// DefaultCohorts returns a copy of the default cohort boundaries:
+// Greatest Generation 1901–1927, Silent Generation 1928–1945, Boomer 1946–1964,
+// Gen X 1965–1980, Millennial 1981–1996, Gen Z 1997–2012, Gen Alpha 2013–2024
+// and Gen Beta 2025–2039.
+func DefaultCohorts() []Cohort {
+	return append([]Cohort(nil), defaultCohorts...)
+}
+
+// GenerationOption adjusts how Generation classifies birth years.
+type GenerationOption func(*[]Cohort)
+
+// WithCohorts replaces the default cohorts with cohorts. When ranges overlap, the
+// first matching cohort wins.
+func WithCohorts(cohorts ...Cohort) GenerationOption {
+	return func(c *[]Cohort) {
+		*c = cohorts
+	}
+}
+
+// Generation returns the label of the cohort containing the holder's birth year,
+// as decoded by BirthYear, or "" if no cohort contains it.
+// Returns an error if the CNP is invalid.
+func Generation(cnp string, opts ...GenerationOption) (string, error) {
+	year, err := BirthYear(cnp)
+	if err != nil {
+		return "", err
+	}
+	cohorts := defaultCohorts
+	for _, opt := range opts {
+		opt(&cohorts)
+	}
+	for _, c := range cohorts {
+		if year >= c.First && year <= c.Last {
+			return c.Label, nil
+		}
+	}
+	return "", nil
+}
+
+// Granularity selects how coarsely CohortKey generalises the birth date.
+type Granularity int
+
+// Supported granularities, from coarsest to finest.
+const (
+	ByDecade Granularity = iota // "1980s": the birth decade only
+	ByYear                      // "1985": the birth year
+	ByMonth                     // "1985-03": the birth year and month
+)
+
+// CohortKey validates the CNP and returns a key built only from its birth date
+// generalised to granularity; gender, residency, county, serial and, at every
+// granularity, the day of birth are dropped. ByDecade reveals the decade,
+// ByYear the year and ByMonth the year and month. Returns an error if the CNP is
+// invalid or the granularity is unsupported.
+func CohortKey(cnp string, granularity Granularity) (string, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	year := c.BirthDate.Year()
+	switch granularity {
+	case ByDecade:
+		return fmt.Sprintf("%ds", year/10*10), nil
+	case ByYear:
+		return fmt.Sprintf("%d", year), nil
+	case ByMonth:
+		return fmt.Sprintf("%d-%02d", year, int(c.BirthDate.Month())), nil
+	default:
+		return "", fmt.Errorf("unsupported granularity %d", granularity)
+	}
+}
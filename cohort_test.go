@@ -0,0 +1,75 @@
+// ABOUTME: Tests for generation labels and cohort keys.
+package rossn
+
+import "testing"
+
+func TestGeneration(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		want string
+	}{
+		{buildCNP("1", "45", "12", "31", "12", "123"), "Silent Generation"},
+		{buildCNP("1", "46", "01", "01", "12", "123"), "Boomer"},
+		{buildCNP("2", "80", "12", "31", "12", "123"), "Gen X"},
+		{buildCNP("1", "81", "01", "01", "12", "123"), "Millennial"},
+		{buildCNP("2", "99", "06", "15", "12", "123"), "Gen Z"},
+		{buildCNP("5", "12", "06", "15", "12", "123"), "Gen Z"},
+		{buildCNP("6", "15", "06", "15", "12", "123"), "Gen Alpha"},
+		{buildCNP("5", "25", "06", "15", "12", "123"), "Gen Beta"},
+		{buildCNP("3", "80", "06", "15", "12", "123"), ""}, // 1880
+	}
+	for _, tc := range cases {
+		got, err := Generation(tc.cnp)
+		if err != nil || got != tc.want {
+			t.Errorf("Generation(%s) = %q, %v; want %q", tc.cnp, got, err, tc.want)
+		}
+	}
+
+	custom := WithCohorts(Cohort{"Pre-1990", 1800, 1989}, Cohort{"1990s", 1990, 1999})
+	if got, _ := Generation(buildCNP("1", "95", "06", "15", "12", "123"), custom); got != "1990s" {
+		t.Errorf("Custom cohorts: got %q, want 1990s", got)
+	}
+	if got, _ := Generation(buildCNP("3", "80", "06", "15", "12", "123"), custom); got != "Pre-1990" {
+		t.Errorf("Custom cohorts: got %q, want Pre-1990", got)
+	}
+	if got, _ := Generation(buildCNP("5", "05", "06", "15", "12", "123"), custom); got != "" {
+		t.Errorf("Years outside every cohort should yield \"\", got %q", got)
+	}
+
+	d := DefaultCohorts()
+	d[0].Label = "changed"
+	if DefaultCohorts()[0].Label == "changed" {
+		t.Errorf("DefaultCohorts should return a copy")
+	}
+
+	if _, err := Generation("123"); err == nil {
+		t.Errorf("Generation should fail for an invalid CNP")
+	}
+}
+
+func TestCohortKey(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		g    Granularity
+		want string
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), ByDecade, "1980s"},
+		{buildCNP("1", "85", "03", "17", "40", "123"), ByYear, "1985"},
+		{buildCNP("1", "85", "03", "17", "40", "123"), ByMonth, "1985-03"},
+		{buildCNP("2", "85", "03", "01", "12", "001"), ByMonth, "1985-03"}, // same key across gender, day, county
+		{buildCNP("3", "99", "12", "31", "12", "123"), ByDecade, "1890s"},
+		{buildCNP("6", "04", "11", "30", "52", "456"), ByMonth, "2004-11"},
+	}
+	for _, tc := range cases {
+		got, err := CohortKey(tc.cnp, tc.g)
+		if err != nil || got != tc.want {
+			t.Errorf("CohortKey(%s, %d) = %q, %v; want %q", tc.cnp, tc.g, got, err, tc.want)
+		}
+	}
+	if _, err := CohortKey("123", ByYear); err == nil {
+		t.Errorf("CohortKey should fail for an invalid CNP")
+	}
+	if _, err := CohortKey(buildCNP("1", "85", "03", "17", "40", "123"), Granularity(9)); err == nil {
+		t.Errorf("CohortKey should fail for an unsupported granularity")
+	}
+}
@@ -0,0 +1,192 @@
+// ABOUTME: Candidate corrections for CNPs mistyped during data entry.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TranspositionCandidates returns the valid CNPs obtained from cnp by swapping
+// one pair of adjacent, different characters, the classic data-entry error that
+// changing single digits cannot fix. The result is sorted in ascending order and
+// holds at most 12 entries, one per adjacent pair; since only different
+// characters are swapped, entries are distinct and never equal cnp itself.
+// Returns nil if no swap yields a CNP passing Validate.
+func TranspositionCandidates(cnp string) []string {
+	if len(cnp) != cnpLength {
+		return nil
+	}
+	var out []string
+	b := []byte(cnp)
+	for i := 0; i+1 < len(b); i++ {
+		if b[i] == b[i+1] {
+			continue
+		}
+		b[i], b[i+1] = b[i+1], b[i]
+		if candidate := string(b); Validate(candidate) == nil {
+			out = append(out, candidate)
+		}
+		b[i], b[i+1] = b[i+1], b[i]
+	}
+	sort.Strings(out)
+	return out
+}
+
+// paddingOffsets are the positions where RepairPadding tries a lost leading
+// zero: the start of the month, day and county fields.
+var paddingOffsets = []int{offMM, offDD, offJJ}
+
+// RepairPadding recovers a CNP in which a leading zero of the month, day or
+// county was dropped, leaving 12 digits. It inserts a '0' at the start of each
+// of those fields and returns, sorted, the results that pass Validate; they are
+// distinct, since equal insertions would need a "00" month or day. The slice is
+// empty if none validate. Returns ErrInvalidLength or
+// ErrNonDigit if cnp is not exactly 12 ASCII digits.
+func RepairPadding(cnp string) ([]string, error) {
+	if len(cnp) != cnpLength-1 {
+		return nil, ErrInvalidLength
+	}
+	if !isStructural(cnp + "0") {
+		return nil, ErrNonDigit
+	}
+	out := []string{}
+	for _, off := range paddingOffsets {
+		candidate := cnp[:off] + "0" + cnp[off:]
+		if Validate(candidate) == nil {
+			out = append(out, candidate)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ConfusionPair is two characters that OCR commonly misreads as each other.
+// Pairs are undirected: either character may be replaced by the other.
+type ConfusionPair [2]byte
+
+// defaultOCRConfusions are the digit pairs most often confused by OCR of printed
+// and typed documents, where closed or broken loops and strokes blur glyphs.
+var defaultOCRConfusions = []ConfusionPair{
+	{'0', '8'}, {'3', '8'}, {'6', '8'}, {'0', '6'}, {'5', '6'},
+	{'0', '9'}, {'4', '9'}, {'1', '7'}, {'2', '7'},
+}
+
+// DefaultOCRConfusions returns a copy of the confusion pairs OCRCandidates uses
+// by default. Append to it to extend the table.
+func DefaultOCRConfusions() []ConfusionPair {
+	return append([]ConfusionPair(nil), defaultOCRConfusions...)
+}
+
+// maxOCRCandidates caps the number of CNPs returned by OCRCandidates.
+const maxOCRCandidates = 12
+
+// OCRCandidates returns the valid CNPs obtained from an invalid, OCR-scanned cnp
+// by replacing one character with its counterpart in a confusion pair. pairs
+// replaces DefaultOCRConfusions when given. The result is sorted in ascending
+// order, free of duplicates, and truncated to the first 12 entries. Returns nil
+// if cnp already passes Validate, is not 13 bytes long, or no substitution
+// yields a valid CNP.
+func OCRCandidates(cnp string, pairs ...ConfusionPair) []string {
+	if len(cnp) != cnpLength || Validate(cnp) == nil {
+		return nil
+	}
+	if len(pairs) == 0 {
+		pairs = defaultOCRConfusions
+	}
+	seen := make(map[string]struct{})
+	var out []string
+	b := []byte(cnp)
+	for i, orig := range b {
+		for _, p := range pairs {
+			var repl byte
+			switch orig {
+			case p[0]:
+				repl = p[1]
+			case p[1]:
+				repl = p[0]
+			default:
+				continue
+			}
+			b[i] = repl
+			candidate := string(b)
+			b[i] = orig
+			if _, dup := seen[candidate]; dup || Validate(candidate) != nil {
+				continue
+			}
+			seen[candidate] = struct{}{}
+			out = append(out, candidate)
+		}
+	}
+	sort.Strings(out)
+	if len(out) > maxOCRCandidates {
+		out = out[:maxOCRCandidates]
+	}
+	return out
+}
+
+// maxDateCorrectionDays caps the number of dates DateCorrectionCandidates tries.
+const maxDateCorrectionDays = 3660
+
+// DateCorrectionCandidates repairs a CNP whose birth date was transcribed wrong
+// while its S digit, county and serial are trusted. It tries every calendar date
+// from from to to, inclusive and clipped to the century the S digit encodes,
+// recomputes the control digit, and returns sorted the results that pass
+// Validate, other than cnp itself. At most 3660 dates (about ten years) are
+// tried, starting from the clipped from; narrow the range to search beyond that.
+// Each date yields at most one CNP, so there are no duplicates. Returns nil if
+// cnp is not 13 ASCII digits or has an unassigned S digit.
+func DateCorrectionCandidates(cnp string, from, to time.Time) []string {
+	if !isStructural(cnp) {
+		return nil
+	}
+	century := sCentury(cnp[offS])
+	if century == 0 {
+		return nil
+	}
+	first := calendarDate(from)
+	if lo := time.Date(century, 1, 1, 0, 0, 0, 0, time.UTC); first.Before(lo) {
+		first = lo
+	}
+	last := calendarDate(to)
+	if hi := time.Date(century+99, 12, 31, 0, 0, 0, 0, time.UTC); last.After(hi) {
+		last = hi
+	}
+	var out []string
+	for i, d := 0, first; i < maxDateCorrectionDays && !d.After(last); i, d = i+1, d.AddDate(0, 0, 1) {
+		base := cnp[:offYY] + d.Format("060102") + cnp[offJJ:offC]
+		candidate := base + strconv.Itoa(controlDigit(base))
+		if candidate != cnp && Validate(candidate) == nil {
+			out = append(out, candidate)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SwapMonthDayCandidate repairs a CNP whose month and day were entered in each
+// other's place, such as month 25 and day 03. If cnp fails Validate with
+// ErrInvalidDate, it swaps the MM and DD fields, recomputes the control digit,
+// and returns the result with ok set when that passes Validate. ok is false if
+// cnp is valid, fails for another reason, or the swap does not yield a valid
+// CNP. Returns ErrInvalidLength or ErrNonDigit if cnp is not 13 ASCII digits.
+func SwapMonthDayCandidate(cnp string) (string, bool, error) {
+	if len(cnp) != cnpLength {
+		return "", false, ErrInvalidLength
+	}
+	if !isStructural(cnp) {
+		return "", false, ErrNonDigit
+	}
+	if !errors.Is(Validate(cnp), ErrInvalidDate) {
+		return "", false, nil
+	}
+	base := cnp[:offMM] + cnp[offDD:offJJ] + cnp[offMM:offDD] + cnp[offJJ:offC]
+	candidate := base + strconv.Itoa(controlDigit(base))
+	if Validate(candidate) != nil {
+		return "", false, nil
+	}
+	return candidate, true, nil
+}
@@ -0,0 +1,229 @@
+// ABOUTME: Tests for typo correction candidates.
+package rossn
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestTranspositionCandidates(t *testing.T) {
+	valid := buildCNP("1", "85", "03", "17", "40", "123")
+	for i := 0; i+1 < len(valid); i++ {
+		if valid[i] == valid[i+1] {
+			continue
+		}
+		b := []byte(valid)
+		b[i], b[i+1] = b[i+1], b[i]
+		typo := string(b)
+		got := TranspositionCandidates(typo)
+		found := false
+		for _, c := range got {
+			if c == typo {
+				t.Errorf("Candidates for %s should not include the input", typo)
+			}
+			if err := Validate(c); err != nil {
+				t.Errorf("Candidate %s for %s should be valid: %v", c, typo, err)
+			}
+			if c == valid {
+				found = true
+			}
+		}
+		// The original is recoverable unless the typo is itself valid.
+		if Validate(typo) != nil && !found {
+			t.Errorf("Swap at %d: %s should be suggested for %s, got %v", i, valid, typo, got)
+		}
+		if !sort.StringsAreSorted(got) || len(got) > 12 {
+			t.Errorf("Candidates for %s should be sorted and capped: %v", typo, got)
+		}
+		for j := 1; j < len(got); j++ {
+			if got[j] == got[j-1] {
+				t.Errorf("Candidates for %s contain a duplicate: %v", typo, got)
+			}
+		}
+	}
+
+	for _, in := range []string{"", "123", "0000000000000"} {
+		if got := TranspositionCandidates(in); got != nil {
+			t.Errorf("TranspositionCandidates(%q) = %v, want nil", in, got)
+		}
+	}
+}
+
+func TestRepairPadding(t *testing.T) {
+	cases := []struct {
+		valid string
+		drop  int // index of the zero that was lost
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), offMM},
+		{buildCNP("2", "85", "11", "07", "40", "123"), offDD},
+		{buildCNP("1", "85", "11", "17", "05", "123"), offJJ},
+	}
+	for _, tc := range cases {
+		typo := tc.valid[:tc.drop] + tc.valid[tc.drop+1:]
+		got, err := RepairPadding(typo)
+		if err != nil {
+			t.Errorf("RepairPadding(%s) returned error: %v", typo, err)
+			continue
+		}
+		if !contains(got, tc.valid) || !sort.StringsAreSorted(got) {
+			t.Errorf("RepairPadding(%s) = %v, want sorted candidates including %s", typo, got, tc.valid)
+		}
+		for _, c := range got {
+			if Validate(c) != nil {
+				t.Errorf("Candidate %s should be valid", c)
+			}
+		}
+	}
+
+	// No insertion can fix a wrong control digit; the result is empty, not nil.
+	bad := cases[0].valid[:offMM] + cases[0].valid[offMM+1:12] + string('0'+(cases[0].valid[12]-'0'+1)%10)
+	if got, err := RepairPadding(bad); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("RepairPadding(%s) = %#v, %v; want an empty slice", bad, got, err)
+	}
+
+	if _, err := RepairPadding("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Short input should fail with ErrInvalidLength, got %v", err)
+	}
+	if _, err := RepairPadding("18503174012a"); !errors.Is(err, ErrNonDigit) {
+		t.Errorf("Non-digit input should fail with ErrNonDigit, got %v", err)
+	}
+}
+
+func TestOCRCandidates(t *testing.T) {
+	valid := buildCNP("1", "85", "03", "17", "40", "123")
+	for i := 0; i < len(valid); i++ {
+		for _, p := range DefaultOCRConfusions() {
+			var scanned byte
+			switch valid[i] {
+			case p[0]:
+				scanned = p[1]
+			case p[1]:
+				scanned = p[0]
+			default:
+				continue
+			}
+			misread := valid[:i] + string(scanned) + valid[i+1:]
+			got := OCRCandidates(misread)
+			if Validate(misread) == nil {
+				if got != nil {
+					t.Errorf("Valid input %s should yield no candidates, got %v", misread, got)
+				}
+				continue
+			}
+			if !sort.StringsAreSorted(got) || len(got) > maxOCRCandidates {
+				t.Errorf("Candidates for %s should be sorted and capped: %v", misread, got)
+			}
+			found := false
+			for j, c := range got {
+				if err := Validate(c); err != nil {
+					t.Errorf("Candidate %s for %s should be valid: %v", c, misread, err)
+				}
+				if j > 0 && got[j-1] == c {
+					t.Errorf("Candidates for %s contain duplicate %s", misread, c)
+				}
+				found = found || c == valid
+			}
+			if !found && len(got) < maxOCRCandidates {
+				t.Errorf("Misread %c→%c at %d: %s should be suggested for %s, got %v", valid[i], scanned, i, valid, misread, got)
+			}
+		}
+	}
+
+	// A custom table replaces the defaults, which do not pair 4 with 5.
+	misread := valid[:2] + "4" + valid[3:] // year 85 read as 84
+	if contains(OCRCandidates(misread), valid) {
+		t.Errorf("Default pairs should not recover %s from %s", valid, misread)
+	}
+	if got := OCRCandidates(misread, ConfusionPair{'4', '5'}); !contains(got, valid) {
+		t.Errorf("Custom pair 4↔5 should recover %s from %s, got %v", valid, misread, got)
+	}
+
+	for _, in := range []string{"", "123", valid} {
+		if got := OCRCandidates(in); got != nil {
+			t.Errorf("OCRCandidates(%q) = %v, want nil", in, got)
+		}
+	}
+
+	DefaultOCRConfusions()[0] = ConfusionPair{'x', 'y'}
+	if DefaultOCRConfusions()[0] != (ConfusionPair{'0', '8'}) {
+		t.Errorf("DefaultOCRConfusions should return a copy")
+	}
+}
+
+func TestDateCorrectionCandidates(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	want := buildCNP("1", "85", "03", "17", "40", "123")
+	garbled := "1853117401230" // 31 Nov 1985, wrong control digit
+
+	got := DateCorrectionCandidates(garbled, date(1985, 3, 1), date(1985, 3, 31))
+	if len(got) != 31 || !contains(got, want) || !sort.StringsAreSorted(got) {
+		t.Errorf("Candidates for March 1985 = %v, want 31 sorted CNPs including %s", got, want)
+	}
+	for _, c := range got {
+		if err := Validate(c); err != nil || c[:1] != "1" || c[7:12] != "40123" {
+			t.Errorf("Candidate %s should be valid with S, county and serial kept: %v", c, err)
+		}
+	}
+
+	// The range is clipped to the S digit's century: S=5 covers 2000–2099 only.
+	s5 := buildCNP("5", "00", "01", "01", "12", "001")
+	got = DateCorrectionCandidates(s5, date(1999, 12, 30), date(2000, 1, 2))
+	if len(got) != 1 || got[0] != buildCNP("5", "00", "01", "02", "12", "001") {
+		t.Errorf("Clipped candidates = %v, want only 2000-01-02 (2000-01-01 is the input)", got)
+	}
+
+	// County 47 is only valid before 1979-12-19.
+	archival := buildCNP("1", "79", "12", "01", "47", "001")
+	got = DateCorrectionCandidates(archival, date(1979, 12, 17), date(1979, 12, 20))
+	if len(got) != 2 {
+		t.Errorf("Archival candidates = %v, want 1979-12-17 and 1979-12-18", got)
+	}
+
+	if got := DateCorrectionCandidates(garbled, date(1900, 1, 1), date(1999, 12, 31)); len(got) != maxDateCorrectionDays {
+		t.Errorf("A century-wide range should be capped at %d dates, got %d", maxDateCorrectionDays, len(got))
+	}
+	for _, bad := range []string{"123", "0853117401230", "18531174012x0"} {
+		if got := DateCorrectionCandidates(bad, date(1985, 1, 1), date(1985, 12, 31)); got != nil {
+			t.Errorf("DateCorrectionCandidates(%s) = %v, want nil", bad, got)
+		}
+	}
+	if got := DateCorrectionCandidates(garbled, date(1985, 2, 1), date(1985, 1, 1)); got != nil {
+		t.Errorf("An empty range should yield nil, got %v", got)
+	}
+}
+
+func TestSwapMonthDayCandidate(t *testing.T) {
+	want := buildCNP("1", "85", "03", "25", "40", "123")
+	swapped := buildCNP("1", "85", "25", "03", "40", "123")
+	got, ok, err := SwapMonthDayCandidate(swapped)
+	if err != nil || !ok || got != want {
+		t.Errorf("SwapMonthDayCandidate(%s) = %s, %v, %v; want %s", swapped, got, ok, err, want)
+	}
+	// The control digit is recomputed, so a stale one does not matter.
+	stale := swapped[:12] + string('0'+(swapped[12]-'0'+1)%10)
+	if got, ok, _ := SwapMonthDayCandidate(stale); !ok || got != want {
+		t.Errorf("SwapMonthDayCandidate(%s) = %s, %v; want %s", stale, got, ok, want)
+	}
+
+	noHelp := []string{
+		want, // already valid
+		buildCNP("1", "85", "25", "31", "40", "123"),            // day 25, month 31: still impossible
+		buildCNP("1", "85", "03", "25", "49", "123"),            // bad county, date fine
+		buildCNP("1", "85", "25", "03", "47", "123"),            // swapped date fine, county 47 not after 1979
+		buildCNP("1", "85", "03", "25", "40", "123")[:12] + "0", // bad control digit only
+	}
+	for _, cnp := range noHelp {
+		if got, ok, err := SwapMonthDayCandidate(cnp); ok || got != "" || err != nil {
+			t.Errorf("SwapMonthDayCandidate(%s) = %s, %v, %v; want no candidate", cnp, got, ok, err)
+		}
+	}
+
+	if _, _, err := SwapMonthDayCandidate("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Short input = %v, want ErrInvalidLength", err)
+	}
+	if _, _, err := SwapMonthDayCandidate("18525034x1231"); !errors.Is(err, ErrNonDigit) {
+		t.Errorf("Non-digit input = %v, want ErrNonDigit", err)
+	}
+}
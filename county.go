@@ -0,0 +1,248 @@
+// ABOUTME: County (JJ) codes used in Romanian CNP numbers and their names.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrCountyMismatch is returned when a CNP's county code differs from the one expected.
+var ErrCountyMismatch = errors.New("CNP county does not match expected county")
+
+// countyNames maps every county code that can appear in a valid CNP to its name.
+var countyNames = map[string]string{
+	"01": "Alba", "02": "Arad", "03": "Argeș", "04": "Bacău",
+	"05": "Bihor", "06": "Bistrița-Năsăud", "07": "Botoșani", "08": "Brașov",
+	"09": "Brăila", "10": "Buzău", "11": "Caraș-Severin", "12": "Cluj",
+	"13": "Constanța", "14": "Covasna", "15": "Dâmbovița", "16": "Dolj",
+	"17": "Galați", "18": "Gorj", "19": "Harghita", "20": "Hunedoara",
+	"21": "Ialomița", "22": "Iași", "23": "Ilfov", "24": "Maramureș",
+	"25": "Mehedinți", "26": "Mureș", "27": "Neamț", "28": "Olt",
+	"29": "Prahova", "30": "Satu Mare", "31": "Sălaj", "32": "Sibiu",
+	"33": "Suceava", "34": "Teleorman", "35": "Timiș", "36": "Tulcea",
+	"37": "Vaslui", "38": "Vâlcea", "39": "Vrancea", "40": "București",
+	"41": "București Sector 1", "42": "București Sector 2", "43": "București Sector 3",
+	"44": "București Sector 4", "45": "București Sector 5", "46": "București Sector 6",
+	"47": "București Sector 7 (historic)", "48": "București Sector 8 (historic)",
+	"51": "Călărași", "52": "Giurgiu",
+	"70": "Any county (foreign residents, SIIEASC)",
+}
+
+// ValidCountiesForDate returns, in ascending order, the county codes that Validate
+// accepts for a CNP with S digit s and the given birth date (its calendar date).
+// The result is empty if s is not 1–9 or the date lies outside the century s encodes.
+func ValidCountiesForDate(birth time.Time, s byte) []string {
+	birth = calendarDate(birth)
+	codes := []string{}
+	century := sCentury(s)
+	if century == 0 || birth.Year() < century || birth.Year() >= century+100 {
+		return codes
+	}
+	for i := 1; i <= 99; i++ {
+		code := fmt.Sprintf("%02d", i)
+		if countyAllowed(code, s, birth) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ValidateExpectingCounty validates the CNP and checks that its JJ field equals
+// county, returning ErrCountyMismatch otherwise. Codes are compared literally, so
+// a Bucharest sector (41–46), a historic code (47, 48) or 70 matches only itself,
+// not "40". Returns an error if county is not a known two-digit code.
+func ValidateExpectingCounty(cnp string, county string) error {
+	if _, ok := countyNames[county]; !ok {
+		return fmt.Errorf("invalid expected county %q", county)
+	}
+	if err := Validate(cnp); err != nil {
+		return err
+	}
+	if fields(cnp).jj != county {
+		return ErrCountyMismatch
+	}
+	return nil
+}
+
+// ValidSDigits returns, in ascending order, the S digits that Validate accepts
+// for a CNP with the given county code and birth date (its calendar date). It is
+// the converse of ValidCountiesForDate: county 70 before 2024 narrows the result
+// to 7, 8 and 9. The result is empty if no S digit can encode the combination.
+func ValidSDigits(county string, birth time.Time) []byte {
+	birth = calendarDate(birth)
+	digits := []byte{}
+	for s := byte('1'); s <= '9'; s++ {
+		century := sCentury(s)
+		if birth.Year() < century || birth.Year() >= century+100 {
+			continue
+		}
+		if countyAllowed(county, s, birth) {
+			digits = append(digits, s)
+		}
+	}
+	return digits
+}
+
+// Constraint kinds reported in CountyRule.Constraint.
+const (
+	CountyAlways           = "always"               // valid for every S digit and birth date
+	CountyArchival         = "born_before"          // valid only for birth dates before BornBefore
+	CountyForeignOrSIIEASC = "foreign_or_from_year" // valid for SDigits, or any S from AnySFromYear
+)
+
+// CountyRule describes, in a form that can be serialised for clients, when a
+// county code is accepted by Validate.
+type CountyRule struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Constraint   string `json:"constraint"`
+	BornBefore   string `json:"born_before,omitempty"`     // YYYY-MM-DD, for CountyArchival
+	SDigits      string `json:"s_digits,omitempty"`        // S digits always allowed, for CountyForeignOrSIIEASC
+	AnySFromYear int    `json:"any_s_from_year,omitempty"` // first birth year allowing any S, for CountyForeignOrSIIEASC
+}
+
+// CountyRules returns a rule for every county code Validate can accept, in
+// ascending order of code. The rules mirror the checks Validate applies and are
+// built from the same constants, so clients can render county choices or
+// reimplement the check from them.
+func CountyRules() []CountyRule {
+	rules := make([]CountyRule, 0, len(countyNames))
+	for i := 1; i <= 99; i++ {
+		code := fmt.Sprintf("%02d", i)
+		name, ok := countyNames[code]
+		if !ok {
+			continue
+		}
+		r := CountyRule{Code: code, Name: name, Constraint: CountyAlways}
+		switch code {
+		case "47", "48":
+			r.Constraint = CountyArchival
+			r.BornBefore = archivalCutoff.Format("2006-01-02")
+		case "70":
+			r.Constraint = CountyForeignOrSIIEASC
+			r.SDigits = string([]byte{SForeignMale, SForeignFemale, SNonResident})
+			r.AnySFromYear = siieascStartYear
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// RangeForDate returns the smallest and largest valid CNPs, in string order, for
+// Romanian citizens of the given gender born on the calendar date of birth:
+// serial 001 in the lowest county valid for the date and serial 999 in the
+// highest. Every such citizen's CNP lies between the two, so they bound a range
+// query on a sorted index. Foreign residents (S=7/8) use other S digits and fall
+// outside the range. Returns an error for an unsupported gender or a date
+// outside 1800–2099.
+func RangeForDate(birth time.Time, gender Gender) (min, max string, err error) {
+	if gender != Male && gender != Female {
+		return "", "", fmt.Errorf("invalid gender %q", gender)
+	}
+	birth = calendarDate(birth)
+	if y := birth.Year(); y < 1800 || y > 2099 {
+		return "", "", fmt.Errorf("%w: %s is outside 1800–2099", ErrInvalidDate, birth.Format("2006-01-02"))
+	}
+	s := sDigitFor(gender, birth.Year(), false)
+	codes := ValidCountiesForDate(birth, s)
+	lo, _ := strconv.Atoi(codes[0])
+	hi, _ := strconv.Atoi(codes[len(codes)-1])
+	y, m, d := birth.Year(), int(birth.Month()), birth.Day()
+	return assemble(s, y, m, d, lo, 1), assemble(s, y, m, d, hi, 999), nil
+}
+
+// serialsPerPrefix is the number of serials (001–999) available to each
+// combination of S digit, birth date and county.
+const serialsPerPrefix = 999
+
+// MaxRecordsFor returns the theoretical maximum number of distinct valid CNPs
+// for a county and birth date: 999 serials for each S digit ValidSDigits allows,
+// so 999 per gender and residency class (4995 for an ordinary 19xx date, which
+// S=1, 2, 7, 8 and 9 can all encode). An auditor seeing more records than this
+// for one county and date has found duplicates. Returns 0 if county is not a
+// known code or is not valid for the date.
+func MaxRecordsFor(county string, birth time.Time) int {
+	if _, ok := countyNames[county]; !ok {
+		return 0
+	}
+	return serialsPerPrefix * len(ValidSDigits(county, birth))
+}
+
+// IsCountyCodeKnown reports whether code is a JJ value that is valid in some
+// CNP: 01–46, 51 and 52, the archival 47 and 48, or 70. It ignores the birth
+// date and S digit, so it suits a cheap first-pass check on a county field
+// entered on its own; a full CNP still needs Validate.
+func IsCountyCodeKnown(code string) bool {
+	_, ok := countyNames[code]
+	return ok
+}
+
+// IsCountyCodeAlwaysValid reports whether code is valid for every birth date
+// and S digit, which holds for 01–46, 51 and 52 but not for 47, 48 or 70.
+func IsCountyCodeAlwaysValid(code string) bool {
+	return isStandardCounty(code)
+}
+
+// ValidCount returns the exact number of 13-digit strings that pass Validate,
+// derived in closed form rather than by enumeration. The count sums, over each
+// S digit and each day of the century it encodes, the counties valid that day
+// times the 999 serials; exactly one control digit completes each prefix.
+// Placeholder values that would otherwise pass are then subtracted.
+func ValidCount() int64 {
+	standard := 0
+	for _, ok := range standardCounties {
+		if ok {
+			standard++
+		}
+	}
+	siieascStart := time.Date(siieascStartYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	var count int64
+	for s := SMale1900; s <= SNonResident; s++ {
+		c := sCentury(s)
+		first := time.Date(c, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(c+100, 1, 1, 0, 0, 0, 0, time.UTC)
+		days := daysBetween(first, end)
+		prefixes := days * standard
+		prefixes += 2 * overlapDays(first, end, time.Time{}, archivalCutoff) // 47 and 48
+		if s >= SForeignMale {
+			prefixes += days // 70 for foreign residents and non-residents
+		} else {
+			prefixes += overlapDays(first, end, siieascStart, end) // 70 under SIIEASC
+		}
+		count += int64(prefixes) * serialsPerPrefix
+	}
+	unguarded := newConfig([]Option{Placeholders()})
+	for _, p := range defaultPlaceholders {
+		if unguarded.validate(p) == nil {
+			count--
+		}
+	}
+	return count
+}
+
+// ValidFraction returns the fraction of all 10^13 strings of 13 ASCII digits
+// that pass Validate, ValidCount()/10^13, about 0.16%: roughly one random digit
+// string in 611 is a valid CNP. Most fail on the date or county, and of those
+// with a valid prefix nine in ten fail on the control digit.
+func ValidFraction() float64 {
+	return float64(ValidCount()) / 1e13
+}
+
+// overlapDays returns the number of days in both [a0, a1) and [b0, b1), all
+// at midnight UTC, or 0 if the ranges do not overlap.
+func overlapDays(a0, a1, b0, b1 time.Time) int {
+	if b0.After(a0) {
+		a0 = b0
+	}
+	if b1.Before(a1) {
+		a1 = b1
+	}
+	if !a0.Before(a1) {
+		return 0
+	}
+	return daysBetween(a0, a1)
+}
@@ -0,0 +1,294 @@
+// ABOUTME: Tests for county code tables and date-dependent county queries.
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidCountiesForDate(t *testing.T) {
+	standard := "01,02,03,04,05,06,07,08,09,10,11,12,13,14,15,16,17,18,19,20," +
+		"21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40," +
+		"41,42,43,44,45,46"
+	cases := []struct {
+		birth time.Time
+		s     byte
+		want  string
+	}{
+		{time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC), '1', standard + ",47,48,51,52"},
+		{time.Date(1979, 12, 19, 0, 0, 0, 0, time.UTC), '1', standard + ",51,52"},
+		{time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), '7', standard + ",51,52,70"},
+		{time.Date(1970, 6, 15, 0, 0, 0, 0, time.UTC), '9', standard + ",47,48,51,52,70"},
+		{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), '5', standard + ",51,52"},
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), '6', standard + ",51,52,70"},
+		{time.Date(1880, 1, 1, 0, 0, 0, 0, time.UTC), '3', standard + ",47,48,51,52"},
+		{time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), '5', ""}, // S=5 is 20xx
+		{time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), '0', ""},
+	}
+	for _, tc := range cases {
+		got := strings.Join(ValidCountiesForDate(tc.birth, tc.s), ",")
+		if got != tc.want {
+			t.Errorf("ValidCountiesForDate(%s, %c) = %s, want %s", tc.birth.Format("2006-01-02"), tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestValidSDigits(t *testing.T) {
+	cases := []struct {
+		county string
+		birth  time.Time
+		want   string
+	}{
+		{"12", time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC), "12789"},
+		{"12", time.Date(1885, 3, 1, 0, 0, 0, 0, time.UTC), "34"},
+		{"12", time.Date(2005, 3, 1, 0, 0, 0, 0, time.UTC), "56"},
+		{"70", time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC), "789"},
+		{"70", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), ""},
+		{"70", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "56"},
+		{"47", time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC), "12789"},
+		{"47", time.Date(1979, 12, 19, 0, 0, 0, 0, time.UTC), ""},
+		{"49", time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tc := range cases {
+		got := ValidSDigits(tc.county, tc.birth)
+		if got == nil || string(got) != tc.want {
+			t.Errorf("ValidSDigits(%s, %s) = %q, want %q", tc.county, tc.birth.Format("2006-01-02"), got, tc.want)
+		}
+	}
+}
+
+// Every county returned for a date must produce a CNP that passes Validate,
+// and every other code must fail.
+func TestValidCountiesForDate_AgreesWithValidate(t *testing.T) {
+	dates := []time.Time{
+		time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	for _, birth := range dates {
+		for s := byte('1'); s <= '9'; s++ {
+			allowed := map[string]bool{}
+			for _, code := range ValidCountiesForDate(birth, s) {
+				allowed[code] = true
+			}
+			if sCentury(s) != birth.Year()/100*100 {
+				continue
+			}
+			for jj := 0; jj <= 99; jj++ {
+				cnp := assemble(s, birth.Year(), int(birth.Month()), birth.Day(), jj, 123)
+				if ok := Validate(cnp) == nil; ok != allowed[cnp[7:9]] {
+					t.Errorf("County %s for S=%c on %s: Validate=%v, listed=%v", cnp[7:9], s, birth.Format("2006-01-02"), ok, allowed[cnp[7:9]])
+				}
+			}
+		}
+	}
+}
+
+func TestValidateExpectingCounty(t *testing.T) {
+	cases := []struct {
+		cnp    string
+		county string
+		want   error
+	}{
+		{buildCNP("1", "80", "01", "01", "12", "001"), "12", nil},
+		{buildCNP("1", "80", "01", "01", "12", "001"), "13", ErrCountyMismatch},
+		{buildCNP("1", "80", "01", "01", "41", "001"), "41", nil},
+		{buildCNP("1", "80", "01", "01", "41", "001"), "40", ErrCountyMismatch},
+		{buildCNP("1", "75", "01", "01", "47", "001"), "47", nil},
+		{buildCNP("7", "90", "01", "01", "70", "001"), "70", nil},
+		{"123", "12", ErrInvalidLength},
+	}
+	for _, tc := range cases {
+		if err := ValidateExpectingCounty(tc.cnp, tc.county); !errors.Is(err, tc.want) {
+			t.Errorf("ValidateExpectingCounty(%s, %s) = %v, want %v", tc.cnp, tc.county, err, tc.want)
+		}
+	}
+	for _, county := range []string{"", "1", "99", "49"} {
+		if err := ValidateExpectingCounty(buildCNP("1", "80", "01", "01", "12", "001"), county); err == nil {
+			t.Errorf("Unknown expected county %q should be rejected", county)
+		}
+	}
+}
+
+// A client evaluating CountyRules generically must reach the same verdict as
+// Validate for every code, S digit and date.
+func TestCountyRules_AgreeWithValidate(t *testing.T) {
+	rules := map[string]CountyRule{}
+	for _, r := range CountyRules() {
+		rules[r.Code] = r
+	}
+	if len(rules) != len(countyNames) {
+		t.Fatalf("CountyRules has %d codes, countyNames %d", len(rules), len(countyNames))
+	}
+	allowed := func(code string, s byte, birth time.Time) bool {
+		r, ok := rules[code]
+		if !ok {
+			return false
+		}
+		switch r.Constraint {
+		case CountyAlways:
+			return true
+		case CountyArchival:
+			return birth.Format("2006-01-02") < r.BornBefore
+		case CountyForeignOrSIIEASC:
+			return strings.IndexByte(r.SDigits, s) >= 0 || birth.Year() >= r.AnySFromYear
+		}
+		t.Fatalf("Unknown constraint %q", r.Constraint)
+		return false
+	}
+	dates := []time.Time{
+		time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(1979, 12, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, birth := range dates {
+		for s := byte('1'); s <= '9'; s++ {
+			for i := 0; i <= 99; i++ {
+				code := fmt.Sprintf("%02d", i)
+				if got, want := allowed(code, s, birth), countyAllowed(code, s, birth); got != want {
+					t.Errorf("County %s, S=%c, %s: rules say %v, Validate %v", code, s, birth.Format("2006-01-02"), got, want)
+				}
+			}
+		}
+	}
+	if r := rules["47"]; r.BornBefore != "1979-12-19" || r.Name == "" {
+		t.Errorf("Unexpected rule for 47: %+v", r)
+	}
+}
+
+func TestRangeForDate(t *testing.T) {
+	cases := []struct {
+		birth    time.Time
+		gender   Gender
+		min, max string
+	}{
+		{time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), Male,
+			buildCNP("1", "85", "03", "17", "01", "001"), buildCNP("1", "85", "03", "17", "52", "999")},
+		{time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC), Female,
+			buildCNP("2", "79", "12", "18", "01", "001"), buildCNP("2", "79", "12", "18", "52", "999")},
+		{time.Date(1880, 1, 1, 0, 0, 0, 0, time.UTC), Female,
+			buildCNP("4", "80", "01", "01", "01", "001"), buildCNP("4", "80", "01", "01", "52", "999")},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), Male,
+			buildCNP("5", "24", "02", "29", "01", "001"), buildCNP("5", "24", "02", "29", "70", "999")},
+	}
+	for _, tc := range cases {
+		min, max, err := RangeForDate(tc.birth, tc.gender)
+		if err != nil || min != tc.min || max != tc.max {
+			t.Errorf("RangeForDate(%s, %s) = %s, %s, %v; want %s, %s",
+				tc.birth.Format("2006-01-02"), tc.gender, min, max, err, tc.min, tc.max)
+		}
+		if Validate(min) != nil || Validate(max) != nil {
+			t.Errorf("Range bounds %s, %s should be valid", min, max)
+		}
+	}
+
+	// Every valid citizen CNP for the date lies within the range.
+	birth := time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC)
+	min, max, _ := RangeForDate(birth, Female)
+	for i := 0; i < 500; i++ {
+		cnp, _ := GenerateInCounty(fmt.Sprintf("%02d", 1+i%46), birth, birth, Female)
+		if cnp < min || cnp > max {
+			t.Errorf("%s is outside [%s, %s]", cnp, min, max)
+		}
+	}
+
+	if _, _, err := RangeForDate(time.Date(1799, 12, 31, 0, 0, 0, 0, time.UTC), Male); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("Dates before 1800 should fail with ErrInvalidDate, got %v", err)
+	}
+	if _, _, err := RangeForDate(birth, Gender("X")); err == nil {
+		t.Errorf("Unknown gender should fail")
+	}
+}
+
+func TestMaxRecordsFor(t *testing.T) {
+	cases := []struct {
+		county string
+		birth  time.Time
+		want   int
+	}{
+		{"12", time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), 5 * 999}, // S=1, 2, 7, 8, 9
+		{"12", time.Date(2005, 3, 17, 0, 0, 0, 0, time.UTC), 2 * 999}, // S=5, 6
+		{"70", time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), 3 * 999}, // S=7, 8, 9
+		{"70", time.Date(2010, 3, 17, 0, 0, 0, 0, time.UTC), 0},
+		{"47", time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), 0},
+		{"49", time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), 0},
+		{"", time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tc := range cases {
+		if got := MaxRecordsFor(tc.county, tc.birth); got != tc.want {
+			t.Errorf("MaxRecordsFor(%q, %s) = %d, want %d", tc.county, tc.birth.Format("2006-01-02"), got, tc.want)
+		}
+	}
+}
+
+func TestIsCountyCodeKnown(t *testing.T) {
+	for i := 0; i <= 100; i++ {
+		code := fmt.Sprintf("%02d", i)
+		always := (i >= 1 && i <= 46) || i == 51 || i == 52
+		known := always || i == 47 || i == 48 || i == 70
+		if got := IsCountyCodeKnown(code); got != known {
+			t.Errorf("IsCountyCodeKnown(%q) = %v, want %v", code, got, known)
+		}
+		if got := IsCountyCodeAlwaysValid(code); got != always {
+			t.Errorf("IsCountyCodeAlwaysValid(%q) = %v, want %v", code, got, always)
+		}
+	}
+	for _, code := range []string{"", "1", "001", " 1", "1a", "٠١"} {
+		if IsCountyCodeKnown(code) || IsCountyCodeAlwaysValid(code) {
+			t.Errorf("Malformed code %q should be unknown", code)
+		}
+	}
+}
+
+func TestValidCount(t *testing.T) {
+	// Enumerate every S digit and date, asking countyAllowed about the three
+	// date-dependent codes, to cross-check the closed form.
+	standard := 0
+	for i := 1; i <= 99; i++ {
+		if IsCountyCodeAlwaysValid(fmt.Sprintf("%02d", i)) {
+			standard++
+		}
+	}
+	var want int64
+	for s := SMale1900; s <= SNonResident; s++ {
+		c := sCentury(s)
+		for d := time.Date(c, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() < c+100; d = d.AddDate(0, 0, 1) {
+			counties := standard
+			for _, code := range []string{"47", "48", "70"} {
+				if countyAllowed(code, s, d) {
+					counties++
+				}
+			}
+			want += int64(counties) * 999
+		}
+	}
+	if got := ValidCount(); got != want {
+		t.Errorf("ValidCount() = %d, enumeration gives %d", got, want)
+	}
+	if got := ValidFraction(); got != float64(want)/1e13 {
+		t.Errorf("ValidFraction() = %v, want %v", got, float64(want)/1e13)
+	}
+
+	// A sample of random digit strings should agree within sampling error.
+	r := rand.New(rand.NewPCG(7, 11))
+	const n = 1_000_000
+	hits := 0
+	b := make([]byte, cnpLength)
+	for i := 0; i < n; i++ {
+		for j := range b {
+			b[j] = byte('0' + r.IntN(10))
+		}
+		if Validate(string(b)) == nil {
+			hits++
+		}
+	}
+	if expected := ValidFraction() * n; float64(hits) < expected*0.85 || float64(hits) > expected*1.15 {
+		t.Errorf("%d of %d random strings are valid, expected about %.0f", hits, n, expected)
+	}
+}
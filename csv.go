@@ -0,0 +1,186 @@
+// ABOUTME: Analysis of CSV input, inferring which column holds CNPs.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// CSVReport summarises the CNP content of a CSV stream, column by column.
+type CSVReport struct {
+	Header  bool           // whether the first record was taken as a header and skipped
+	Rows    int            // data records read, excluding the header
+	Column  int            // index of the inferred CNP column, -1 if none holds a valid CNP
+	Columns []ColumnReport // one entry per column, in column order
+}
+
+// ColumnReport is the validation Report for one CSV column. Blank cells are
+// skipped without being counted, and Errors holds at most ReportErrorLimit
+// failures, each with the input line of its cell.
+type ColumnReport struct {
+	Name string // header value, or "" without a header
+	Report
+}
+
+// Ratio returns the fraction of the column's non-blank cells that are valid CNPs,
+// or 0 for a column with no non-blank cells.
+func (c ColumnReport) Ratio() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Valid) / float64(c.Total)
+}
+
+// CNPColumn returns the report of the inferred CNP column, or false if none was inferred.
+func (r CSVReport) CNPColumn() (ColumnReport, bool) {
+	if r.Column < 0 {
+		return ColumnReport{}, false
+	}
+	return r.Columns[r.Column], true
+}
+
+// AnalyzeCSV reads CSV records from r, validates every non-blank cell, and
+// infers the column that holds CNPs: the one with the most valid values, ties
+// going to the higher valid ratio and then to the lower index. Records may have
+// differing lengths. The first record is taken as a header if none of its cells
+// is made of digits only.
+//
+// If fn is non-nil and a CNP column was inferred, AnalyzeCSV then seeks r back
+// to where it started and calls fn for every data record with the input line of
+// its cell in that column, the trimmed cell and the validation error. A blank
+// cell is reported with ErrInvalidLength, and a record with too few fields with
+// an empty CNP and ErrRecordTooShort. The column is known only once the stream
+// ends, hence the second pass and the io.ReadSeeker. Records are streamed and
+// not retained in either pass, so memory grows only with the number of columns.
+//
+// On a read, seek or parse error, the report so far is returned with the error.
+func AnalyzeCSV(r io.ReadSeeker, fn func(line int, cnp string, err error)) (CSVReport, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return CSVReport{Column: -1}, err
+	}
+	rep, err := analyzeCSV(r)
+	if err != nil || fn == nil || rep.Column < 0 {
+		return rep, err
+	}
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return rep, err
+	}
+	return rep, validateCSVColumn(r, rep.Column, rep.Header, fn)
+}
+
+// analyzeCSV is the single pass of AnalyzeCSV building the report.
+func analyzeCSV(r io.Reader) (CSVReport, error) {
+	rep := CSVReport{Column: -1}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rep.Column = bestColumn(rep.Columns)
+			return rep, err
+		}
+		for len(rep.Columns) < len(record) {
+			rep.Columns = append(rep.Columns, ColumnReport{Report: Report{Errors: []LineError{}}})
+		}
+		if first && isHeaderRecord(record) {
+			rep.Header = true
+			for i, name := range record {
+				rep.Columns[i].Name = strings.TrimSpace(name)
+			}
+			continue
+		}
+		rep.Rows++
+		for i, cell := range record {
+			cnp := strings.TrimSpace(cell)
+			if cnp == "" {
+				continue
+			}
+			col := &rep.Columns[i]
+			col.Total++
+			if err := Validate(cnp); err != nil {
+				col.Invalid++
+				if len(col.Errors) < ReportErrorLimit {
+					line, _ := cr.FieldPos(i)
+					col.Errors = append(col.Errors, LineError{Line: line, CNP: cnp, Err: err})
+				}
+				continue
+			}
+			col.Valid++
+		}
+	}
+	rep.Column = bestColumn(rep.Columns)
+	return rep, nil
+}
+
+// validateCSVColumn reads CSV records from r and calls fn for the cell in
+// column of every data record, as described for AnalyzeCSV. If header is true,
+// the first record is skipped.
+func validateCSVColumn(r io.Reader, column int, header bool, fn func(line int, cnp string, err error)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if first && header {
+			continue
+		}
+		if column >= len(record) {
+			line, _ := cr.FieldPos(0)
+			fn(line, "", ErrRecordTooShort)
+			continue
+		}
+		line, _ := cr.FieldPos(column)
+		cnp := strings.TrimSpace(record[column])
+		fn(line, cnp, Validate(cnp))
+	}
+}
+
+// isHeaderRecord reports whether no cell of record consists solely of ASCII digits.
+func isHeaderRecord(record []string) bool {
+	for _, cell := range record {
+		if isDigits(strings.TrimSpace(cell)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s is non-empty and made of ASCII digits only.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isASCIIDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// bestColumn returns the index of the column with the most valid CNPs, breaking
+// ties by valid ratio and then by lower index, or -1 if no column has any.
+func bestColumn(cols []ColumnReport) int {
+	best := -1
+	for i, c := range cols {
+		if c.Valid == 0 {
+			continue
+		}
+		if best < 0 || c.Valid > cols[best].Valid || (c.Valid == cols[best].Valid && c.Ratio() > cols[best].Ratio()) {
+			best = i
+		}
+	}
+	return best
+}
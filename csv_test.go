@@ -0,0 +1,118 @@
+// ABOUTME: Tests for CSV analysis and CNP column inference.
+package rossn
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeCSV(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	input := "id,name,cnp,phone\n" +
+		"1,Ana," + a + ",0722000000\n" +
+		"2,Ion, " + b + " ,0722000001\n" +
+		"3,Dan,1850317401230,\n" + // bad control digit
+		"4,\"Pop, Maria\",,0722000003\n" + // blank CNP
+		"5,Eva," + a + ",0722000004,extra\n"
+	rep, err := AnalyzeCSV(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("AnalyzeCSV returned error: %v", err)
+	}
+	if !rep.Header || rep.Rows != 5 || rep.Column != 2 || len(rep.Columns) != 5 {
+		t.Fatalf("Unexpected report: header=%v rows=%d column=%d columns=%d", rep.Header, rep.Rows, rep.Column, len(rep.Columns))
+	}
+	col, ok := rep.CNPColumn()
+	if !ok || col.Name != "cnp" || col.Total != 4 || col.Valid != 3 || col.Invalid != 1 || col.Ratio() != 0.75 {
+		t.Errorf("Unexpected CNP column report: %+v", col)
+	}
+	if len(col.Errors) != 1 || col.Errors[0].Line != 4 || !errors.Is(col.Errors[0], ErrInvalidControlDigit) {
+		t.Errorf("Unexpected CNP column errors: %v", col.Errors)
+	}
+	if rep.Columns[0].Valid != 0 || rep.Columns[0].Total != 5 || rep.Columns[4].Total != 1 {
+		t.Errorf("Unexpected per-column totals: %+v", rep.Columns)
+	}
+}
+
+func TestAnalyzeCSV_Inference(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	cases := []struct {
+		name   string
+		input  string
+		header bool
+		column int
+	}{
+		{"no header", a + ",x\n" + b + ",y\n", false, 0},
+		{"most valid wins", "1," + a + "," + a + "\n2,bad," + b + "\n", false, 2},
+		{"tie goes to higher ratio", a + "," + a + "\n123,\n", false, 1},
+		{"tie and equal ratio goes to lower index", a + "," + b + "\n", false, 0},
+		{"no CNPs", "name\nAna\n", true, -1},
+		{"empty", "", false, -1},
+	}
+	for _, tc := range cases {
+		rep, err := AnalyzeCSV(strings.NewReader(tc.input), nil)
+		if err != nil || rep.Header != tc.header || rep.Column != tc.column {
+			t.Errorf("%s: header=%v column=%d err=%v; want header=%v column=%d", tc.name, rep.Header, rep.Column, err, tc.header, tc.column)
+		}
+	}
+	if _, ok := (CSVReport{Column: -1}).CNPColumn(); ok {
+		t.Errorf("CNPColumn should report false when no column was inferred")
+	}
+}
+
+func TestAnalyzeCSV_ParseError(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	rep, err := AnalyzeCSV(strings.NewReader(a+"\n\"unterminated\n"), nil)
+	if err == nil {
+		t.Fatalf("AnalyzeCSV should report the parse error")
+	}
+	if rep.Rows != 1 || rep.Column != 0 {
+		t.Errorf("The report so far should be returned, got rows=%d column=%d", rep.Rows, rep.Column)
+	}
+}
+
+func TestAnalyzeCSV_Rows(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	input := "id,cnp\n" +
+		"1," + a + "\n" +
+		"2, 1850317401230 \n" + // bad control digit
+		"3,\n" + // blank CNP
+		"4\n"
+	// The second pass rewinds to where r started, not to its beginning.
+	r := strings.NewReader("skipped\n" + input)
+	r.Seek(int64(len("skipped\n")), io.SeekStart)
+	type row struct {
+		line int
+		cnp  string
+		err  error
+	}
+	var got []row
+	rep, err := AnalyzeCSV(r, func(line int, cnp string, err error) {
+		got = append(got, row{line, cnp, err})
+	})
+	if err != nil || rep.Column != 1 || rep.Rows != 4 {
+		t.Fatalf("AnalyzeCSV = column %d, rows %d, %v", rep.Column, rep.Rows, err)
+	}
+	want := []row{
+		{2, a, nil},
+		{3, "1850317401230", ErrInvalidControlDigit},
+		{4, "", ErrInvalidLength},
+		{5, "", ErrRecordTooShort},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Got %d rows, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].line != want[i].line || got[i].cnp != want[i].cnp || !errors.Is(got[i].err, want[i].err) {
+			t.Errorf("Row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	called := false
+	if _, err := AnalyzeCSV(strings.NewReader("name\nAna\n"), func(int, string, error) { called = true }); err != nil || called {
+		t.Errorf("fn should not be called without a CNP column, got called=%v, %v", called, err)
+	}
+}
@@ -0,0 +1,65 @@
+// ABOUTME: Cross-record data-quality checks: likely re-registrations and checksum conflicts.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// SamePersonLikely reports whether two valid CNPs share gender and birth date
+// but differ in county or serial, the pattern left when someone is registered
+// again in another county. CNPs are meant to be unique per person, so this is
+// only a heuristic for reconciling merged datasets; identical CNPs are the same
+// record and report false. Two S=9 CNPs, which encode no gender, match on date
+// alone. Returns an error if either CNP is invalid.
+func SamePersonLikely(a, b string) (bool, error) {
+	ca, err := Parse(a)
+	if err != nil {
+		return false, err
+	}
+	cb, err := Parse(b)
+	if err != nil {
+		return false, err
+	}
+	if ca.Gender != cb.Gender || !ca.BirthDate.Equal(cb.BirthDate) {
+		return false, nil
+	}
+	return ca.County != cb.County || ca.Serial != cb.Serial, nil
+}
+
+// FindChecksumConflicts groups inputs that share the first 12 digits but differ
+// in the control digit. Since the control digit is determined by the other 12,
+// at most one CNP of each group can be correct, which signals data corruption.
+// Only strings of exactly 13 ASCII digits are considered. Each group lists its
+// distinct values in order of first appearance, and groups are ordered by the
+// first appearance of their prefix. Returns nil if there are no conflicts.
+func FindChecksumConflicts(cnps []string) [][]string {
+	var prefixes []string
+	groups := map[string][]string{}
+	for _, cnp := range cnps {
+		if !isStructural(cnp) {
+			continue
+		}
+		prefix := cnp[:offC]
+		group, seen := groups[prefix]
+		if !seen {
+			prefixes = append(prefixes, prefix)
+		}
+		if !contains(group, cnp) {
+			groups[prefix] = append(group, cnp)
+		}
+	}
+	var conflicts [][]string
+	for _, prefix := range prefixes {
+		if g := groups[prefix]; len(g) > 1 {
+			conflicts = append(conflicts, g)
+		}
+	}
+	return conflicts
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
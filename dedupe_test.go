@@ -0,0 +1,66 @@
+// ABOUTME: Tests for cross-record data-quality checks.
+package rossn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSamePersonLikely(t *testing.T) {
+	base := buildCNP("1", "85", "03", "17", "40", "123")
+	cases := []struct {
+		b    string
+		want bool
+	}{
+		{buildCNP("1", "85", "03", "17", "12", "045"), true}, // other county and serial
+		{buildCNP("1", "85", "03", "17", "40", "124"), true}, // same county, other serial
+		{buildCNP("7", "85", "03", "17", "12", "045"), true}, // now a foreign resident
+		{base, false}, // the same record
+		{buildCNP("2", "85", "03", "17", "12", "045"), false}, // other gender
+		{buildCNP("1", "85", "03", "18", "12", "045"), false}, // other date
+		{buildCNP("5", "85", "03", "17", "12", "045"), false}, // 2085, not 1985
+	}
+	for _, tc := range cases {
+		got, err := SamePersonLikely(base, tc.b)
+		if err != nil {
+			t.Errorf("SamePersonLikely(%s, %s) returned error: %v", base, tc.b, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("SamePersonLikely(%s, %s) = %v, want %v", base, tc.b, got, tc.want)
+		}
+	}
+
+	if _, err := SamePersonLikely(base, "123"); err == nil {
+		t.Errorf("SamePersonLikely should fail for an invalid CNP")
+	}
+	if _, err := SamePersonLikely("123", base); err == nil {
+		t.Errorf("SamePersonLikely should fail for an invalid CNP")
+	}
+}
+
+func TestFindChecksumConflicts(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	aBad := a[:12] + string('0'+(a[12]-'0'+1)%10)
+	aWorse := a[:12] + string('0'+(a[12]-'0'+2)%10)
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	bBad := b[:12] + string('0'+(b[12]-'0'+5)%10)
+	c := buildCNP("5", "01", "01", "01", "30", "101")
+
+	got := FindChecksumConflicts([]string{
+		c, b, a, aBad, "bad", bBad, a, aWorse, "１２３４５６７８９０１", c,
+	})
+	want := [][]string{{b, bBad}, {a, aBad, aWorse}}
+	if len(got) != len(want) {
+		t.Fatalf("FindChecksumConflicts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if strings.Join(got[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("Group %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := FindChecksumConflicts([]string{a, a, b, c}); got != nil {
+		t.Errorf("Duplicates without conflicting control digits are not conflicts: %v", got)
+	}
+}
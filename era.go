@@ -0,0 +1,34 @@
+// ABOUTME: Heuristic classification of the system that likely issued a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// Labels returned by IssueEra.
+const (
+	EraPre1978 = "pre-1978 legacy"
+	Era1978    = "1978–1999"
+	Era2000s   = "2000s"
+	EraSIIEASC = "SIIEASC 2024+"
+)
+
+// IssueEra returns a coarse label for the system that likely issued the CNP:
+// county 70 with a birth year of 2024 or later is EraSIIEASC (the same rule
+// Validate applies to code 70); otherwise the label follows the birth year.
+// This is a heuristic for bucketing records, since people born before 1978
+// received their CNP retroactively. Returns an error if the CNP is invalid.
+func IssueEra(cnp string) (string, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	switch year := c.BirthDate.Year(); {
+	case c.County == "70" && isSIIEASC(c.BirthDate):
+		return EraSIIEASC, nil
+	case year < 1978:
+		return EraPre1978, nil
+	case year < 2000:
+		return Era1978, nil
+	default:
+		return Era2000s, nil
+	}
+}
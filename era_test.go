@@ -0,0 +1,33 @@
+// ABOUTME: Tests for the IssueEra heuristic.
+package rossn
+
+import "testing"
+
+func TestIssueEra(t *testing.T) {
+	tests := []struct {
+		cnp  string
+		want string
+	}{
+		{buildCNP("1", "65", "06", "15", "01", "001"), EraPre1978},
+		{buildCNP("3", "85", "06", "15", "01", "001"), EraPre1978}, // 1885
+		{buildCNP("2", "78", "01", "01", "12", "123"), Era1978},
+		{buildCNP("1", "99", "12", "31", "40", "001"), Era1978},
+		{buildCNP("5", "05", "03", "10", "22", "045"), Era2000s},
+		{buildCNP("5", "24", "03", "10", "22", "045"), Era2000s},
+		{buildCNP("6", "24", "03", "10", "70", "045"), EraSIIEASC},
+		{buildCNP("7", "90", "03", "10", "70", "045"), Era1978},
+	}
+	for _, tt := range tests {
+		got, err := IssueEra(tt.cnp)
+		if err != nil {
+			t.Errorf("IssueEra(%s) returned error: %v", tt.cnp, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("IssueEra(%s) = %q, want %q", tt.cnp, got, tt.want)
+		}
+	}
+	if _, err := IssueEra("123"); err == nil {
+		t.Errorf("IssueEra should reject invalid CNPs")
+	}
+}
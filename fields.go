@@ -0,0 +1,41 @@
+// ABOUTME: Generic, map-based access to the decoded components of a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+// Keys of the map returned by Fields. They are part of the stable API.
+const (
+	FieldGender     = "gender"      // "M", "F", or "" for S=9 (non-resident, no gender encoded)
+	FieldBirthDate  = "birth_date"  // century-aware birth date as YYYY-MM-DD
+	FieldCountyCode = "county_code" // two-digit JJ code, e.g. "12"
+	FieldCountyName = "county_name" // county name, e.g. "Cluj"
+	FieldSerial     = "serial"      // three-digit NNN serial, e.g. "001"
+	FieldControl    = "control"     // the control digit, e.g. "7"
+	FieldResidency  = "residency"   // "citizen", "foreign_resident" or "non_resident"
+)
+
+// Fields validates the CNP and returns its decoded components keyed by the
+// Field* constants, for callers that process fields generically (e.g. CSV export).
+// Returns an error if the CNP is invalid.
+func Fields(cnp string) (map[string]string, error) {
+	if err := Validate(cnp); err != nil {
+		return nil, err
+	}
+	f := fields(cnp)
+	return map[string]string{
+		FieldGender:     string(genderOf(f.s)),
+		FieldBirthDate:  formatBirthDate(cnp),
+		FieldCountyCode: f.jj,
+		FieldCountyName: countyNames[f.jj],
+		FieldSerial:     f.nnn,
+		FieldControl:    string(f.c),
+		FieldResidency:  residencyCode(f.s),
+	}, nil
+}
+
+// residencyCode classifies the S digit: 1–6 are Romanian citizens, 7–8 foreign
+// residents, and 9 non-residents.
+func residencyCode(s byte) string {
+	m, _ := sInfo(s)
+	return string(m.residency)
+}
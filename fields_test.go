@@ -0,0 +1,53 @@
+// ABOUTME: Tests for the map-based field accessor.
+package rossn
+
+import "testing"
+
+func TestFields(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		want map[string]string
+	}{
+		{buildCNP("1", "80", "01", "01", "12", "001"), map[string]string{
+			FieldGender: "M", FieldBirthDate: "1980-01-01", FieldCountyCode: "12",
+			FieldCountyName: "Cluj", FieldSerial: "001", FieldResidency: "citizen",
+		}},
+		{buildCNP("4", "99", "12", "31", "40", "999"), map[string]string{
+			FieldGender: "F", FieldBirthDate: "1899-12-31", FieldCountyCode: "40",
+			FieldCountyName: "București", FieldSerial: "999", FieldResidency: "citizen",
+		}},
+		{buildCNP("8", "85", "03", "12", "70", "111"), map[string]string{
+			FieldGender: "F", FieldBirthDate: "1985-03-12", FieldCountyCode: "70",
+			FieldSerial: "111", FieldResidency: "foreign_resident",
+		}},
+		{buildCNP("9", "90", "01", "01", "41", "555"), map[string]string{
+			FieldGender: "", FieldBirthDate: "1990-01-01", FieldCountyCode: "41",
+			FieldCountyName: "București Sector 1", FieldSerial: "555", FieldResidency: "non_resident",
+		}},
+	}
+	for _, tc := range cases {
+		got, err := Fields(tc.cnp)
+		if err != nil {
+			t.Errorf("Fields(%s) returned error: %v", tc.cnp, err)
+			continue
+		}
+		if len(got) != 7 {
+			t.Errorf("Fields(%s) returned %d keys, want 7", tc.cnp, len(got))
+		}
+		for k, v := range tc.want {
+			if got[k] != v {
+				t.Errorf("Fields(%s)[%q] = %q, want %q", tc.cnp, k, got[k], v)
+			}
+		}
+		if got[FieldControl] != tc.cnp[12:] {
+			t.Errorf("Fields(%s)[%q] = %q, want %q", tc.cnp, FieldControl, got[FieldControl], tc.cnp[12:])
+		}
+		if got[FieldCountyName] == "" {
+			t.Errorf("Fields(%s) has no county name", tc.cnp)
+		}
+	}
+
+	if _, err := Fields("123"); err == nil {
+		t.Errorf("Fields should fail for an invalid CNP")
+	}
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Gender as encoded by the S digit of a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrGenderMismatch is returned when a CNP's S digit encodes a different gender
+// from the one expected.
+var ErrGenderMismatch = errors.New("CNP gender does not match expected gender")
+
+// ErrNoGender is returned by FlipGender for a valid non-resident CNP (S=9),
+// whose S digit encodes no gender.
+var ErrNoGender = errors.New("CNP S digit encodes no gender")
+
+// Gender is the sex encoded by the S digit of a CNP.
+type Gender string
+
+// Genders encoded by the S digit: odd digits are male, even digits female.
+const (
+	Male   Gender = "M"
+	Female Gender = "F"
+)
+
+// genderOf returns Male for odd S digits, Female for even ones, and "" for S=9,
+// which identifies a non-resident without encoding a gender.
+func genderOf(s byte) Gender {
+	m, _ := sInfo(s)
+	return m.gender
+}
+
+// GenderOf validates the CNP and returns the gender its S digit encodes: Male
+// for odd digits and Female for even ones. S=9 is odd but identifies a
+// non-resident without encoding a gender, so, as in CNP.Gender and Fields, it
+// yields "" with a nil error. Returns an error if the CNP is invalid.
+func GenderOf(cnp string) (Gender, error) {
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return genderOf(cnp[offS]), nil
+}
+
+// ValidateExpectingGender validates the CNP and checks that its S digit encodes
+// gender g, returning ErrGenderMismatch otherwise. S=9 (non-resident) encodes no
+// gender, so for such CNPs the gender check is skipped and any g is accepted.
+// Returns an error if g is neither Male nor Female.
+func ValidateExpectingGender(cnp string, g Gender) error {
+	if g != Male && g != Female {
+		return fmt.Errorf("invalid expected gender %q", g)
+	}
+	if err := Validate(cnp); err != nil {
+		return err
+	}
+	if actual := genderOf(fields(cnp).s); actual != "" && actual != g {
+		return ErrGenderMismatch
+	}
+	return nil
+}
+
+// FlipGender validates the CNP and returns it with the S digit changed to the
+// opposite gender in the same century or residency class (1↔2, 3↔4, 5↔6, 7↔8)
+// and the control digit recomputed, for correcting a gender entered wrongly.
+// Returns ErrNoGender for a non-resident's CNP (S=9), which has no gender
+// counterpart, and an error if the CNP is invalid.
+func FlipGender(cnp string) (string, error) {
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	s := cnp[offS]
+	switch {
+	case s == SNonResident:
+		return "", ErrNoGender
+	case (s-'0')%2 == 1:
+		s++ // each female digit directly follows its male counterpart
+	default:
+		s--
+	}
+	base := string(s) + cnp[offYY:offC]
+	return base + strconv.Itoa(controlDigit(base)), nil
+}
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for gender decoding and gender expectation checks.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateExpectingGender(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		g    Gender
+		want error
+	}{
+		{buildCNP("1", "80", "01", "01", "01", "001"), Male, nil},
+		{buildCNP("1", "80", "01", "01", "01", "001"), Female, ErrGenderMismatch},
+		{buildCNP("2", "80", "01", "01", "01", "001"), Female, nil},
+		{buildCNP("2", "80", "01", "01", "01", "001"), Male, ErrGenderMismatch},
+		{buildCNP("4", "80", "01", "01", "01", "001"), Male, ErrGenderMismatch},
+		{buildCNP("5", "01", "01", "01", "01", "001"), Male, nil},
+		{buildCNP("8", "80", "01", "01", "01", "001"), Female, nil},
+		{buildCNP("9", "80", "01", "01", "01", "001"), Male, nil},
+		{buildCNP("9", "80", "01", "01", "01", "001"), Female, nil},
+		{"123", Male, ErrInvalidLength},
+	}
+	for _, tc := range cases {
+		if err := ValidateExpectingGender(tc.cnp, tc.g); !errors.Is(err, tc.want) {
+			t.Errorf("ValidateExpectingGender(%s, %s) = %v, want %v", tc.cnp, tc.g, err, tc.want)
+		}
+	}
+	if err := ValidateExpectingGender(buildCNP("1", "80", "01", "01", "01", "001"), ""); err == nil {
+		t.Errorf("An empty expected gender should be rejected")
+	}
+}
+
+func TestFlipGender(t *testing.T) {
+	pairs := []struct{ from, to, yy, county string }{
+		{"1", "2", "85", "40"}, {"2", "1", "85", "40"},
+		{"3", "4", "50", "47"}, {"4", "3", "50", "12"},
+		{"5", "6", "24", "70"}, {"6", "5", "05", "12"},
+		{"7", "8", "85", "70"}, {"8", "7", "85", "12"},
+	}
+	for _, p := range pairs {
+		in := buildCNP(p.from, p.yy, "03", "17", p.county, "123")
+		want := buildCNP(p.to, p.yy, "03", "17", p.county, "123")
+		got, err := FlipGender(in)
+		if err != nil || got != want {
+			t.Errorf("FlipGender(%s) = %s, %v; want %s", in, got, err, want)
+			continue
+		}
+		if err := Validate(got); err != nil {
+			t.Errorf("FlipGender(%s) = %s is invalid: %v", in, got, err)
+		}
+		if back, _ := FlipGender(got); back != in {
+			t.Errorf("Flipping %s twice gave %s", in, back)
+		}
+	}
+
+	if _, err := FlipGender(buildCNP("9", "85", "03", "17", "70", "123")); !errors.Is(err, ErrNoGender) || errors.Is(err, ErrInvalidGenderDigit) {
+		t.Errorf("FlipGender of S=9 = %v, want ErrNoGender", err)
+	}
+	if _, err := FlipGender("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("FlipGender of an invalid CNP = %v, want ErrInvalidLength", err)
+	}
+}
+
+func TestGenderOf(t *testing.T) {
+	want := map[string]Gender{"1": Male, "2": Female, "3": Male, "4": Female, "5": Male, "6": Female, "7": Male, "8": Female, "9": ""}
+	for s, g := range want {
+		yy := "85"
+		switch s {
+		case "5", "6":
+			yy = "05"
+		}
+		cnp := buildCNP(s, yy, "03", "17", "12", "123")
+		if got, err := GenderOf(cnp); err != nil || got != g {
+			t.Errorf("GenderOf(%s) = %q, %v; want %q", cnp, got, err, g)
+		}
+	}
+	if got, err := GenderOf("123"); !errors.Is(err, ErrInvalidLength) || got != "" {
+		t.Errorf("GenderOf of an invalid CNP = %q, %v; want ErrInvalidLength", got, err)
+	}
+}
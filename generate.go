@@ -0,0 +1,345 @@
+// ABOUTME: Generation of random, valid Romanian CNP numbers for test data.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// ErrUniqueExhausted is returned when a generator cannot produce the requested
+// number of distinct CNPs within its attempt budget.
+var ErrUniqueExhausted = errors.New("could not generate enough unique CNPs")
+
+// Generate returns a random CNP that passes Validate.
+// The S digit, birth date and serial are drawn uniformly from their legal ranges;
+// the county is always one of the unconditionally valid codes (01–46, 51, 52).
+// Two calls may return the same CNP; use GenerateUnique when that matters.
+func Generate() string {
+	return generate(rand.IntN)
+}
+
+// GenerateUnique returns n distinct CNPs, each of which passes Validate.
+// Collisions are retried up to a fixed attempt budget proportional to n;
+// ErrUniqueExhausted is returned if the budget runs out before n values are found.
+func GenerateUnique(n int) ([]string, error) {
+	return generateUnique(n, Generate)
+}
+
+// Parameters of the GenerateIndexed mapping. They are part of its output format:
+// changing any of them changes the CNP returned for a given index.
+const (
+	indexedDays   = 109573 // days from 1800-01-01 to 2099-12-31 inclusive
+	indexedStride = 7919   // prime, coprime to indexedDays, to spread dates
+
+	// IndexedLimit is the number of distinct CNPs GenerateIndexed can return.
+	IndexedLimit = 48 * 2 * indexedDays * 999
+)
+
+// GenerateIndexed deterministically maps i in [0, IndexedLimit) to a distinct
+// valid CNP, the same on every run and platform. The mapping is fixed and will
+// not change: with k = i, county is the (k mod 48)-th of 01–46, 51, 52; then with
+// k /= 48, the gender is male if k is even; then with k /= 2, the birth date is
+// 1800-01-01 plus (k mod 109573)*7919 mod 109573 days, with the matching
+// citizen S digit; and finally the serial is k/109573 + 1.
+// It panics if i is negative or not below IndexedLimit.
+func GenerateIndexed(i int) string {
+	if i < 0 || int64(i) >= IndexedLimit {
+		panic(fmt.Sprintf("rossn: GenerateIndexed index %d out of range", i))
+	}
+	return generateIndexed(int64(i))
+}
+
+// GenerateFromString deterministically derives a valid CNP from seed, such as a
+// username, so demo environments get the same fake identity for the same seed
+// without a mapping table. The first 8 bytes of the seed's SHA-256, taken big
+// endian and reduced modulo IndexedLimit, select the CNP GenerateIndexed would
+// return for that index, so the result is identical on every run and platform.
+func GenerateFromString(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return generateIndexed(int64(binary.BigEndian.Uint64(sum[:8]) % IndexedLimit))
+}
+
+// generateIndexed implements GenerateIndexed for k in [0, IndexedLimit), taking
+// an int64 so indices beyond a 32-bit int are reachable.
+func generateIndexed(k int64) string {
+	county := int(k%48) + 1
+	if county > 46 {
+		county += 4
+	}
+	k /= 48
+	gender := Male
+	if k%2 == 1 {
+		gender = Female
+	}
+	k /= 2
+	offset := int(k % indexedDays * indexedStride % indexedDays)
+	birth := time.Date(1800, 1, 1+offset, 0, 0, 0, 0, time.UTC)
+	serial := int(k/indexedDays) + 1
+	s := sDigitFor(gender, birth.Year(), false)
+	return assemble(s, birth.Year(), int(birth.Month()), birth.Day(), county, serial)
+}
+
+// GenerateInCounty returns a random valid CNP for the given county and gender,
+// with a birth date drawn uniformly from the days in [from, to] (calendar dates,
+// inclusive) that the county allows: 47/48 only before 1979-12-19, and 70 only
+// for 1900–1999 (as a foreign resident, S=7/8) or from 2024 on.
+// Returns an error for an illegal county or gender, or if no date in the range is legal.
+func GenerateInCounty(county string, from, to time.Time, gender Gender) (string, error) {
+	return generateInCounty(rand.IntN, county, from, to, gender)
+}
+
+func generateInCounty(intn func(int) int, county string, from, to time.Time, gender Gender) (string, error) {
+	if _, ok := countyNames[county]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCounty, county)
+	}
+	if gender != Male && gender != Female {
+		return "", fmt.Errorf("invalid gender %q", gender)
+	}
+	from, to = calendarDate(from), calendarDate(to)
+
+	// Clip the requested range to each window the county allows.
+	var spans [][2]time.Time
+	total := 0
+	for _, w := range countyWindows(county) {
+		lo, hi := w[0], w[1]
+		if from.After(lo) {
+			lo = from
+		}
+		if to.Before(hi) {
+			hi = to
+		}
+		if hi.Before(lo) {
+			continue
+		}
+		spans = append(spans, [2]time.Time{lo, hi})
+		total += daysBetween(lo, hi) + 1
+	}
+	if total == 0 {
+		return "", fmt.Errorf("no legal birth date for county %s between %s and %s",
+			county, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	offset := intn(total)
+	var birth time.Time
+	for _, sp := range spans {
+		n := daysBetween(sp[0], sp[1]) + 1
+		if offset < n {
+			birth = sp[0].AddDate(0, 0, offset)
+			break
+		}
+		offset -= n
+	}
+
+	s := sDigitFor(gender, birth.Year(), county == "70")
+	jj, _ := strconv.Atoi(county)
+	cnp := assemble(s, birth.Year(), int(birth.Month()), birth.Day(), jj, 1+intn(999))
+	if err := Validate(cnp); err != nil {
+		return "", fmt.Errorf("generated invalid CNP %s: %w", cnp, err)
+	}
+	return cnp, nil
+}
+
+// GenerateSibling returns a random valid CNP sharing the birth date and county
+// of cnp, with the S digit for gender and a serial different from cnp's, so
+// every input has 998 possible siblings per gender. A foreign resident (S=7/8)
+// keeps that status. A non-resident (S=9) input, whose S digit encodes no
+// gender, yields a foreign-resident sibling (S=7/8) instead.
+// Returns an error if cnp is invalid or gender is neither Male nor Female.
+func GenerateSibling(cnp string, gender Gender) (string, error) {
+	return generateSibling(rand.IntN, cnp, gender)
+}
+
+func generateSibling(intn func(int) int, cnp string, gender Gender) (string, error) {
+	if gender != Male && gender != Female {
+		return "", fmt.Errorf("invalid gender %q", gender)
+	}
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	m, _ := sInfo(c.S)
+	birth := c.BirthDate
+	s := sDigitFor(gender, birth.Year(), m.residency != ResidencyCitizen)
+	serial := 1 + intn(998) // 1–999 without c.Serial
+	if serial >= c.Serial {
+		serial++
+	}
+	jj, _ := strconv.Atoi(c.County)
+	sibling := assemble(s, birth.Year(), int(birth.Month()), birth.Day(), jj, serial)
+	if err := Validate(sibling); err != nil {
+		return "", fmt.Errorf("generated invalid CNP %s: %w", sibling, err)
+	}
+	return sibling, nil
+}
+
+// countyWindows returns the inclusive birth-date ranges for which a CNP with the
+// given county code can be issued to a person of either gender.
+func countyWindows(county string) [][2]time.Time {
+	first := time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+	switch county {
+	case "47", "48":
+		return [][2]time.Time{{first, archivalCutoff.AddDate(0, 0, -1)}}
+	case "70":
+		return [][2]time.Time{
+			{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)},
+			{time.Date(siieascStartYear, 1, 1, 0, 0, 0, 0, time.UTC), last},
+		}
+	default:
+		return [][2]time.Time{{first, last}}
+	}
+}
+
+// sDigitFor returns the S digit encoding gender and the century of year.
+// When foreign is set, 19xx births use the foreign-resident digits 7/8.
+func sDigitFor(gender Gender, year int, foreign bool) byte {
+	var s byte
+	switch {
+	case year < 1900:
+		s = SMale1800
+	case year < 2000 && foreign:
+		s = SForeignMale
+	case year < 2000:
+		s = SMale1900
+	default:
+		s = SMale2000
+	}
+	if gender == Female {
+		s++ // each female digit directly follows its male counterpart
+	}
+	return s
+}
+
+// calendarDate returns midnight UTC of t's calendar date in its own location.
+func calendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of days from a to b, both at midnight UTC.
+// It avoids time.Duration, which cannot span the full 1800–2099 range.
+func daysBetween(a, b time.Time) int {
+	return int((b.Unix() - a.Unix()) / 86400)
+}
+
+// generateUnique collects n distinct values from gen, giving up after
+// 10*n+100 attempts so that tightly constrained generators cannot loop forever.
+func generateUnique(n int, gen func() string) ([]string, error) {
+	if n < 0 {
+		return nil, errors.New("n must not be negative")
+	}
+	seen := make(map[string]struct{}, n)
+	out := make([]string, 0, n)
+	maxAttempts := 10*n + 100
+	for attempts := 0; len(out) < n; attempts++ {
+		if attempts == maxAttempts {
+			return nil, fmt.Errorf("%w: got %d of %d after %d attempts", ErrUniqueExhausted, len(out), n, attempts)
+		}
+		cnp := gen()
+		if _, dup := seen[cnp]; dup {
+			continue
+		}
+		seen[cnp] = struct{}{}
+		out = append(out, cnp)
+	}
+	return out, nil
+}
+
+// generate builds a random valid CNP using intn as the source of randomness.
+func generate(intn func(int) int) string {
+	s := byte('1' + intn(9))
+	year := sCentury(s) + intn(100)
+	month := 1 + intn(12)
+	day := 1 + intn(daysInMonth(year, month))
+	county := 1 + intn(48) // 01–46, then 47→51 and 48→52
+	if county > 46 {
+		county += 4
+	}
+	serial := 1 + intn(999)
+	return assemble(s, year, month, day, county, serial)
+}
+
+// assemble formats the CNP components and appends the control digit.
+func assemble(s byte, year, month, day, county, serial int) string {
+	base := fmt.Sprintf("%c%02d%02d%02d%02d%03d", s, year%100, month, day, county, serial)
+	return base + strconv.Itoa(controlDigit(base))
+}
+
+// sCentury returns the first year of the century encoded by the S digit.
+// Returns 0 for an illegal S.
+func sCentury(s byte) int {
+	m, _ := sInfo(s)
+	return m.century
+}
+
+// SerialIterator returns a function that yields, one per call and in ascending
+// serial order, every valid CNP with the given S digit, two-digit YY, MM, DD and
+// county, computing each control digit on demand rather than building all 999
+// up front. Every value yielded passes Validate; serials that fail it, such as
+// placeholder values, are skipped, so a prefix with an invalid date or county
+// yields nothing. The function returns false once the serial space is exhausted.
+// The module targets Go 1.22, so no iter.Seq form is provided; a range-over-func
+// adapter is a one-line wrapper around this closure on newer Go versions.
+func SerialIterator(s byte, yy, mm, dd, county string) func() (string, bool) {
+	prefix := string(s) + yy + mm + dd + county
+	serial := 0
+	return func() (string, bool) {
+		if len(prefix) != offNNN {
+			return "", false
+		}
+		for serial < 999 {
+			serial++
+			base := prefix + fmt.Sprintf("%03d", serial)
+			if cnp := base + strconv.Itoa(controlDigit(base)); Validate(cnp) == nil {
+				return cnp, true
+			}
+		}
+		return "", false
+	}
+}
+
+// CNPsForDate returns a function that yields, one per call, every valid CNP for
+// the calendar date of birth and gender: for each S digit encoding that gender
+// and the birth century (the citizen digit and, for 1900–1999, the foreign
+// resident one; S=9 encodes no gender and is excluded), each county
+// ValidCountiesForDate accepts, so 47/48 and 70 follow their date and S rules,
+// and each serial 001–999 via SerialIterator. The order is by S digit, then
+// county, then serial, and 999 CNPs are yielded per S digit and county: at most
+// 101×999 = 100,899, for 1900s births before 1979-12-19 (50 counties for the
+// citizen digit, 51 with county 70 for the foreign one). The function returns
+// false once exhausted, or immediately for an unknown gender.
+func CNPsForDate(birth time.Time, gender Gender) func() (string, bool) {
+	birth = calendarDate(birth)
+	yy, mm, dd := fmt.Sprintf("%02d", birth.Year()%100), fmt.Sprintf("%02d", int(birth.Month())), fmt.Sprintf("%02d", birth.Day())
+	var prefixes [][2]string // S digit and county
+	for s := SMale1900; s <= SNonResident; s++ {
+		if m, _ := sInfo(s); gender == "" || m.gender != gender {
+			continue
+		}
+		for _, county := range ValidCountiesForDate(birth, s) {
+			prefixes = append(prefixes, [2]string{string(s), county})
+		}
+	}
+	var serials func() (string, bool)
+	return func() (string, bool) {
+		for {
+			if serials != nil {
+				if cnp, ok := serials(); ok {
+					return cnp, true
+				}
+			}
+			if len(prefixes) == 0 {
+				return "", false
+			}
+			serials = SerialIterator(prefixes[0][0][0], yy, mm, dd, prefixes[0][1])
+			prefixes = prefixes[1:]
+		}
+	}
+}
@@ -0,0 +1,359 @@
+// ABOUTME: Tests for CNP generation, every generated CNP must pass Validate.
+package rossn
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerate_AlwaysValid(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		cnp := Generate()
+		if err := Validate(cnp); err != nil {
+			t.Fatalf("Generated CNP should be valid: %s got error: %v", cnp, err)
+		}
+	}
+}
+
+func TestGenerateUnique(t *testing.T) {
+	cnps, err := GenerateUnique(5000)
+	if err != nil {
+		t.Fatalf("GenerateUnique(5000) failed: %v", err)
+	}
+	if len(cnps) != 5000 {
+		t.Fatalf("GenerateUnique(5000) returned %d CNPs", len(cnps))
+	}
+	seen := make(map[string]bool)
+	for _, cnp := range cnps {
+		if seen[cnp] {
+			t.Errorf("Duplicate CNP returned: %s", cnp)
+		}
+		seen[cnp] = true
+		if err := Validate(cnp); err != nil {
+			t.Errorf("Generated CNP should be valid: %s got error: %v", cnp, err)
+		}
+	}
+
+	if cnps, err := GenerateUnique(0); err != nil || len(cnps) != 0 {
+		t.Errorf("GenerateUnique(0) should return an empty slice, got %v, %v", cnps, err)
+	}
+	if _, err := GenerateUnique(-1); err == nil {
+		t.Errorf("GenerateUnique(-1) should fail")
+	}
+}
+
+func TestGenerateUnique_Exhausted(t *testing.T) {
+	// A generator that can only produce three values cannot satisfy n=4.
+	pool := []string{
+		buildCNP("1", "80", "01", "01", "01", "001"),
+		buildCNP("1", "80", "01", "01", "01", "002"),
+		buildCNP("1", "80", "01", "01", "01", "003"),
+	}
+	i := 0
+	gen := func() string {
+		i++
+		return pool[i%len(pool)]
+	}
+	if _, err := generateUnique(3, gen); err != nil {
+		t.Errorf("3 distinct values should be available: %v", err)
+	}
+	if _, err := generateUnique(4, gen); !errors.Is(err, ErrUniqueExhausted) {
+		t.Errorf("Expected ErrUniqueExhausted, got %v", err)
+	}
+}
+
+func TestGenerateInCounty(t *testing.T) {
+	from := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2000; i++ {
+		gender := Male
+		if i%2 == 1 {
+			gender = Female
+		}
+		cnp, err := GenerateInCounty("12", from, to, gender)
+		if err != nil {
+			t.Fatalf("GenerateInCounty failed: %v", err)
+		}
+		if err := Validate(cnp); err != nil {
+			t.Fatalf("Generated CNP should be valid: %s got error: %v", cnp, err)
+		}
+		if cnp[7:9] != "12" {
+			t.Errorf("Generated CNP has county %s, want 12: %s", cnp[7:9], cnp)
+		}
+		if genderOf(cnp[0]) != gender {
+			t.Errorf("Generated CNP has wrong gender for %s: %s", gender, cnp)
+		}
+		y, m, d := cnpBirthDate(cnp)
+		birth := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+		if birth.Before(from) || birth.After(to) {
+			t.Errorf("Generated birth date %s outside range: %s", birth.Format("2006-01-02"), cnp)
+		}
+	}
+}
+
+func TestGenerateInCounty_SpecialCodes(t *testing.T) {
+	// 47/48 only before the 1979-12-19 cutoff.
+	from := time.Date(1979, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		cnp, err := GenerateInCounty("47", from, to, Male)
+		if err != nil {
+			t.Fatalf("GenerateInCounty(47) failed: %v", err)
+		}
+		if y, m, d := cnpBirthDate(cnp); time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC).After(time.Date(1979, 12, 18, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("JJ=47 generated after cutoff: %s", cnp)
+		}
+		if err := Validate(cnp); err != nil {
+			t.Errorf("Generated CNP should be valid: %s got error: %v", cnp, err)
+		}
+	}
+	if _, err := GenerateInCounty("48", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), to, Female); err == nil {
+		t.Errorf("JJ=48 after the cutoff should be impossible")
+	}
+
+	// 70 spans the foreign-resident 19xx window and the SIIEASC 2024+ window.
+	from = time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	to = time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 500; i++ {
+		cnp, err := GenerateInCounty("70", from, to, Female)
+		if err != nil {
+			t.Fatalf("GenerateInCounty(70) failed: %v", err)
+		}
+		if err := Validate(cnp); err != nil {
+			t.Errorf("Generated CNP should be valid: %s got error: %v", cnp, err)
+		}
+		if y, _, _ := cnpBirthDate(cnp); y >= 2000 && y < 2024 {
+			t.Errorf("JJ=70 generated in the 2000–2023 gap: %s", cnp)
+		}
+	}
+	if _, err := GenerateInCounty("70", time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Male); err == nil {
+		t.Errorf("JJ=70 for 2005–2020 should be impossible")
+	}
+}
+
+func TestGenerateInCounty_Errors(t *testing.T) {
+	from := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := GenerateInCounty("49", from, to, Male); !errors.Is(err, ErrInvalidCounty) {
+		t.Errorf("Illegal county should fail with ErrInvalidCounty, got %v", err)
+	}
+	if _, err := GenerateInCounty("12", to, from, Male); err == nil {
+		t.Errorf("Empty range should fail")
+	}
+	if _, err := GenerateInCounty("12", from, to, Gender("X")); err == nil {
+		t.Errorf("Unknown gender should fail")
+	}
+	// The whole encodable range, wider than a time.Duration can hold.
+	wideFrom := time.Date(1700, 1, 1, 0, 0, 0, 0, time.UTC)
+	wideTo := time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		cnp, err := GenerateInCounty("05", wideFrom, wideTo, Male)
+		if err != nil || Validate(cnp) != nil {
+			t.Fatalf("Wide range should yield a valid CNP, got %s, %v", cnp, err)
+		}
+	}
+	// A single-day range is allowed.
+	cnp, err := GenerateInCounty("01", from, from, Female)
+	if err != nil || cnp[1:7] != "900101" {
+		t.Errorf("Single-day range should yield that date, got %s, %v", cnp, err)
+	}
+}
+
+func TestGenerateSibling(t *testing.T) {
+	cases := []struct {
+		cnp    string
+		gender Gender
+		s      byte
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), Female, '2'},
+		{buildCNP("2", "85", "03", "17", "40", "123"), Female, '2'},
+		{buildCNP("6", "12", "02", "29", "22", "001"), Male, '5'},
+		{buildCNP("4", "85", "03", "17", "05", "999"), Male, '3'},
+		{buildCNP("9", "85", "03", "17", "70", "500"), Female, '8'},
+		{buildCNP("9", "85", "03", "17", "70", "500"), Male, '7'},
+		{buildCNP("7", "85", "03", "17", "40", "500"), Female, '8'},
+		{buildCNP("5", "24", "05", "01", "70", "010"), Female, '6'},
+	}
+	for _, tc := range cases {
+		for i := 0; i < 100; i++ {
+			sib, err := GenerateSibling(tc.cnp, tc.gender)
+			if err != nil {
+				t.Fatalf("GenerateSibling(%s, %s) returned error: %v", tc.cnp, tc.gender, err)
+			}
+			if err := Validate(sib); err != nil {
+				t.Fatalf("Sibling %s should be valid: %v", sib, err)
+			}
+			if sib[0] != tc.s || sib[1:9] != tc.cnp[1:9] || sib[9:12] == tc.cnp[9:12] {
+				t.Fatalf("Sibling %s of %s should have S=%c, the same date and county, and another serial", sib, tc.cnp, tc.s)
+			}
+		}
+	}
+
+	// The serial draw skips the input's serial at both ends of the range.
+	low := buildCNP("1", "85", "03", "17", "40", "001")
+	if sib, _ := generateSibling(func(int) int { return 0 }, low, Male); sib[9:12] != "002" {
+		t.Errorf("Expected serial 002 next to serial 001, got %s", sib)
+	}
+	high := buildCNP("1", "85", "03", "17", "40", "999")
+	if sib, _ := generateSibling(func(n int) int { return n - 1 }, high, Male); sib[9:12] != "998" {
+		t.Errorf("Expected serial 998 next to serial 999, got %s", sib)
+	}
+
+	if _, err := GenerateSibling("123", Male); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Invalid input should fail, got %v", err)
+	}
+	if _, err := GenerateSibling(low, Gender("X")); err == nil {
+		t.Errorf("Unknown gender should fail")
+	}
+}
+
+func TestGenerateIndexed(t *testing.T) {
+	// These values lock down the documented mapping; they must never change.
+	golden := []struct {
+		i    int
+		want string
+	}{
+		{0, "3000101010018"},
+		{1, "3000101020015"},
+		{47, "3000101520018"},
+		{48, "4000101010011"},
+		{96, "3210907010014"},
+		{12345, "4750324100019"},
+		{IndexedLimit - 1, "6780427529990"},
+	}
+	for _, g := range golden {
+		if got := GenerateIndexed(g.i); got != g.want {
+			t.Errorf("GenerateIndexed(%d) = %s, want %s", g.i, got, g.want)
+		}
+	}
+
+	seen := map[string]int{}
+	check := func(i int) {
+		cnp := GenerateIndexed(i)
+		if err := Validate(cnp); err != nil {
+			t.Fatalf("GenerateIndexed(%d) = %s is invalid: %v", i, cnp, err)
+		}
+		if j, dup := seen[cnp]; dup {
+			t.Fatalf("GenerateIndexed(%d) and (%d) both returned %s", i, j, cnp)
+		}
+		seen[cnp] = i
+	}
+	for i := 0; i < 50000; i++ {
+		check(i)
+	}
+	for i := 50000; i < IndexedLimit; i += IndexedLimit / 20011 {
+		check(i)
+	}
+
+	// Every residue of the stride walk must be a distinct day.
+	days := map[int]bool{}
+	for k := 0; k < indexedDays; k++ {
+		days[k*indexedStride%indexedDays] = true
+	}
+	if len(days) != indexedDays {
+		t.Errorf("Stride %d does not permute %d days", indexedStride, indexedDays)
+	}
+	first := time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)
+	if n := daysBetween(first, time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)) + 1; n != indexedDays {
+		t.Errorf("indexedDays = %d, want %d", indexedDays, n)
+	}
+
+	for _, i := range []int{-1, IndexedLimit} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GenerateIndexed(%d) should panic", i)
+				}
+			}()
+			GenerateIndexed(i)
+		}()
+	}
+}
+
+func TestGenerateFromString(t *testing.T) {
+	seeds := []string{"", "alice", "bob", "Alice", "ana.popescu@example.com", "ĂÎȘȚ"}
+	seen := map[string]string{}
+	for _, seed := range seeds {
+		cnp := GenerateFromString(seed)
+		if err := Validate(cnp); err != nil {
+			t.Errorf("GenerateFromString(%q) = %s is invalid: %v", seed, cnp, err)
+		}
+		if again := GenerateFromString(seed); again != cnp {
+			t.Errorf("GenerateFromString(%q) is not deterministic: %s then %s", seed, cnp, again)
+		}
+		if other, dup := seen[cnp]; dup {
+			t.Errorf("GenerateFromString(%q) and (%q) both returned %s", seed, other, cnp)
+		}
+		seen[cnp] = seed
+	}
+
+	// Pinned so the derivation stays stable across releases and platforms.
+	for seed, want := range map[string]string{"alice": "5211125523889", "bob": "1310417270092"} {
+		if got := GenerateFromString(seed); got != want {
+			t.Errorf("GenerateFromString(%q) = %s, want %s", seed, got, want)
+		}
+	}
+}
+
+func TestSerialIterator(t *testing.T) {
+	next := SerialIterator('2', "85", "03", "17", "40")
+	var got []string
+	for cnp, ok := next(); ok; cnp, ok = next() {
+		if err := Validate(cnp); err != nil {
+			t.Errorf("SerialIterator yielded invalid %s: %v", cnp, err)
+		}
+		got = append(got, cnp)
+	}
+	if len(got) != 999 || got[0] != buildCNP("2", "85", "03", "17", "40", "001") || got[998] != buildCNP("2", "85", "03", "17", "40", "999") {
+		t.Errorf("SerialIterator yielded %d CNPs from %v to %v, want serials 001–999", len(got), got[:1], got[len(got)-1:])
+	}
+	if _, ok := next(); ok {
+		t.Errorf("An exhausted iterator should keep returning false")
+	}
+
+	for _, args := range [][4]string{{"85", "02", "30", "40"}, {"85", "03", "17", "49"}, {"85", "03", "17", "4"}} {
+		if cnp, ok := SerialIterator('1', args[0], args[1], args[2], args[3])(); ok {
+			t.Errorf("SerialIterator(%v) should yield nothing, got %s", args, cnp)
+		}
+	}
+}
+
+func TestCNPsForDate(t *testing.T) {
+	cases := []struct {
+		birth  time.Time
+		gender Gender
+		want   int
+	}{
+		{time.Date(1975, 6, 1, 0, 0, 0, 0, time.UTC), Male, (50 + 51) * 999},   // S=1 with 47/48, S=7 with 47/48/70
+		{time.Date(1985, 6, 1, 0, 0, 0, 0, time.UTC), Female, (48 + 49) * 999}, // S=2, S=8 with 70
+		{time.Date(2010, 6, 1, 0, 0, 0, 0, time.UTC), Male, 48 * 999},          // S=5
+		{time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Female, 49 * 999},        // S=6 with SIIEASC 70
+		{time.Date(1850, 6, 1, 0, 0, 0, 0, time.UTC), Female, 50 * 999},        // S=4 with 47/48
+	}
+	for _, tc := range cases {
+		next := CNPsForDate(tc.birth, tc.gender)
+		seen := map[string]bool{}
+		prev := ""
+		for cnp, ok := next(); ok; cnp, ok = next() {
+			if err := Validate(cnp); err != nil {
+				t.Fatalf("CNPsForDate yielded invalid %s: %v", cnp, err)
+			}
+			if c, _ := Parse(cnp); !c.BirthDate.Equal(tc.birth) || c.Gender != tc.gender {
+				t.Fatalf("CNPsForDate(%s, %s) yielded %s for another holder", tc.birth.Format("2006-01-02"), tc.gender, cnp)
+			}
+			key := cnp[offS:offYY] + cnp[offJJ:offC] // S, county, serial
+			if key <= prev {
+				t.Fatalf("CNPsForDate yielded %s out of order or twice", cnp)
+			}
+			prev = key
+			seen[cnp] = true
+		}
+		if len(seen) != tc.want {
+			t.Errorf("CNPsForDate(%s, %s) yielded %d CNPs, want %d", tc.birth.Format("2006-01-02"), tc.gender, len(seen), tc.want)
+		}
+	}
+	if _, ok := CNPsForDate(time.Date(1985, 6, 1, 0, 0, 0, 0, time.UTC), "")(); ok {
+		t.Errorf("An unknown gender should yield nothing")
+	}
+}
@@ -0,0 +1,89 @@
+// ABOUTME: Localised, human-readable descriptions derived from a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotYetBorn is returned when the CNP's birth date is after the reference time.
+var ErrNotYetBorn = errors.New("birth date is after the reference time")
+
+// Language selects the language of localised output.
+type Language int
+
+// Supported languages.
+const (
+	English Language = iota
+	Romanian
+)
+
+// BornAgo returns a phrase such as "43 years ago" (English) or "acum 43 de ani"
+// (Romanian) built from AgeAt(cnp, at). Romanian uses "an" for 1, "ani" for 0 and
+// 2–19, and "de ani" from 20 on, with the last two digits deciding for larger
+// numbers (101 ani, 120 de ani). Returns ErrNotYetBorn if at falls before the
+// birth date, and an error if the CNP is invalid or the language is unsupported.
+func BornAgo(cnp string, at time.Time, lang Language) (string, error) {
+	age, err := AgeAt(cnp, at)
+	if err != nil {
+		return "", err
+	}
+	if age < 0 {
+		return "", ErrNotYetBorn
+	}
+	switch lang {
+	case English:
+		if age == 1 {
+			return "1 year ago", nil
+		}
+		return fmt.Sprintf("%d years ago", age), nil
+	case Romanian:
+		return "acum " + romanianYears(age), nil
+	default:
+		return "", fmt.Errorf("unsupported language %d", lang)
+	}
+}
+
+// romanianYears returns n followed by the correctly inflected Romanian word for years.
+func romanianYears(n int) string {
+	switch {
+	case n == 1:
+		return "1 an"
+	case n%100 == 0 && n != 0, n%100 >= 20:
+		return fmt.Sprintf("%d de ani", n)
+	default:
+		return fmt.Sprintf("%d ani", n)
+	}
+}
+
+// Romanian weekday names indexed by time.Weekday, and month names indexed by
+// time.Month-1. Romanian writes both in lower case.
+var (
+	romanianWeekdays = [7]string{"duminică", "luni", "marți", "miercuri", "joi", "vineri", "sâmbătă"}
+	romanianMonths   = [12]string{
+		"ianuarie", "februarie", "martie", "aprilie", "mai", "iunie",
+		"iulie", "august", "septembrie", "octombrie", "noiembrie", "decembrie",
+	}
+)
+
+// BirthDescription returns the birth date with its weekday, such as
+// "Tuesday, 1 January 1980" (English) or "marți, 1 ianuarie 1980" (Romanian).
+// Returns an error if the CNP is invalid or the language is unsupported.
+func BirthDescription(cnp string, lang Language) (string, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	d := c.BirthDate
+	switch lang {
+	case English:
+		return d.Format("Monday, 2 January 2006"), nil
+	case Romanian:
+		return fmt.Sprintf("%s, %d %s %d", romanianWeekdays[d.Weekday()], d.Day(), romanianMonths[d.Month()-1], d.Year()), nil
+	default:
+		return "", fmt.Errorf("unsupported language %d", lang)
+	}
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for localised descriptions, including Romanian grammar rules.
+package rossn
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBornAgo(t *testing.T) {
+	cnp := buildCNP("1", "80", "06", "15", "12", "123")
+	future := buildCNP("5", "30", "01", "01", "12", "123")
+	cases := []struct {
+		cnp     string
+		at      time.Time
+		lang    Language
+		want    string
+		wantErr error
+	}{
+		{cnp, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), English, "44 years ago", nil},
+		{cnp, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), Romanian, "acum 44 de ani", nil},
+		{cnp, time.Date(1981, 6, 15, 0, 0, 0, 0, time.UTC), English, "1 year ago", nil},
+		{cnp, time.Date(1981, 6, 15, 0, 0, 0, 0, time.UTC), Romanian, "acum 1 an", nil},
+		{cnp, time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), Romanian, "acum 10 ani", nil},
+		{cnp, time.Date(1980, 7, 1, 0, 0, 0, 0, time.UTC), English, "0 years ago", nil},
+		{future, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), English, "", ErrNotYetBorn},
+		{future, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Romanian, "", ErrNotYetBorn},
+	}
+	for _, tc := range cases {
+		got, err := BornAgo(tc.cnp, tc.at, tc.lang)
+		if !errors.Is(err, tc.wantErr) || got != tc.want {
+			t.Errorf("BornAgo(%s, %s, %d) = %q, %v; want %q, %v", tc.cnp, tc.at.Format("2006-01-02"), tc.lang, got, err, tc.want, tc.wantErr)
+		}
+	}
+
+	if _, err := BornAgo("123", time.Now(), English); err == nil {
+		t.Errorf("BornAgo should fail for an invalid CNP")
+	}
+	if _, err := BornAgo(cnp, time.Now(), Language(99)); err == nil {
+		t.Errorf("BornAgo should fail for an unsupported language")
+	}
+}
+
+func TestRomanianYears(t *testing.T) {
+	cases := map[int]string{
+		0: "0 ani", 1: "1 an", 2: "2 ani", 19: "19 ani", 20: "20 de ani",
+		21: "21 de ani", 43: "43 de ani", 99: "99 de ani", 100: "100 de ani",
+		101: "101 ani", 102: "102 ani", 119: "119 ani", 120: "120 de ani",
+	}
+	for n, want := range cases {
+		if got := romanianYears(n); got != want {
+			t.Errorf("romanianYears(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestBirthDescription(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		lang Language
+		want string
+	}{
+		{buildCNP("1", "80", "01", "01", "12", "123"), English, "Tuesday, 1 January 1980"},
+		{buildCNP("1", "80", "01", "01", "12", "123"), Romanian, "marți, 1 ianuarie 1980"},
+		{buildCNP("2", "85", "03", "17", "40", "123"), Romanian, "duminică, 17 martie 1985"},
+		{buildCNP("6", "04", "02", "29", "52", "456"), Romanian, "duminică, 29 februarie 2004"},
+		{buildCNP("3", "99", "12", "31", "12", "123"), Romanian, "duminică, 31 decembrie 1899"},
+		{buildCNP("5", "24", "08", "10", "12", "123"), Romanian, "sâmbătă, 10 august 2024"},
+		{buildCNP("1", "90", "10", "05", "12", "123"), English, "Friday, 5 October 1990"},
+	}
+	for _, tc := range cases {
+		got, err := BirthDescription(tc.cnp, tc.lang)
+		if err != nil || got != tc.want {
+			t.Errorf("BirthDescription(%s, %d) = %q, %v; want %q", tc.cnp, tc.lang, got, err, tc.want)
+		}
+	}
+
+	if romanianWeekdays[time.Monday] != "luni" || romanianMonths[time.May-1] != "mai" {
+		t.Errorf("Romanian name tables are misaligned")
+	}
+
+	if _, err := BirthDescription("123", English); err == nil {
+		t.Errorf("BirthDescription should fail for an invalid CNP")
+	}
+	if _, err := BirthDescription(buildCNP("1", "80", "01", "01", "12", "123"), Language(99)); err == nil {
+		t.Errorf("BirthDescription should fail for an unsupported language")
+	}
+}
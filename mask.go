@@ -0,0 +1,95 @@
+// ABOUTME: Redaction and generalisation of CNPs for logs and analytics.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maskTagLength is the number of hex digits of the SHA-256 digest kept by MaskWithTag.
+const maskTagLength = 4
+
+// mask replaces everything but the first rune (the S digit of a CNP) with '*',
+// one per rune, so the result is valid UTF-8 even for unvalidated input; an
+// invalid first byte is kept as U+FFFD.
+func mask(cnp string) string {
+	if cnp == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(cnp)
+	return string(r) + strings.Repeat("*", utf8.RuneCountInString(cnp[size:]))
+}
+
+// MaskWithTag redacts cnp like "1************" and appends "#" and the last four
+// hex digits of the SHA-256 of the full input, e.g. "1************#9f3c". The same
+// input always yields the same string, so log lines can be grouped by CNP without
+// revealing it; different CNPs get different tags with high, but not certain,
+// probability. The input is not validated.
+func MaskWithTag(cnp string) string {
+	sum := sha256.Sum256([]byte(cnp))
+	tag := hex.EncodeToString(sum[len(sum)-maskTagLength/2:])
+	return mask(cnp) + "#" + tag
+}
+
+// GeneralizeForAnalytics validates the CNP and returns a token revealing only
+// the holder's gender and four-digit birth year, such as "M-1980" or "F-2004";
+// month, day, county, serial and residency are dropped. Non-residents (S=9), whose
+// CNP encodes no gender, get "U". Many people share each token, so it suits
+// age-band dashboards, but it is still personal data in small populations.
+// Returns an error if the CNP is invalid.
+func GeneralizeForAnalytics(cnp string) (string, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	g := string(c.Gender)
+	if g == "" {
+		g = "U"
+	}
+	return fmt.Sprintf("%s-%d", g, c.BirthDate.Year()), nil
+}
+
+// SupportView validates the CNP and returns a form a support agent can confirm
+// with the holder without seeing the number, such as
+// "gender M · born 1980 · Alba · ••••". It reveals the gender ("U" for S=9),
+// birth year and county name, and nothing of the date, serial or control digit.
+// Returns an error if the CNP is invalid.
+func SupportView(cnp string) (string, error) {
+	c, err := Parse(cnp)
+	if err != nil {
+		return "", err
+	}
+	g := string(c.Gender)
+	if g == "" {
+		g = "U"
+	}
+	return fmt.Sprintf("gender %s · born %d · %s · ••••", g, c.BirthDate.Year(), countyNames[c.County]), nil
+}
+
+// linkageKeyBytes is the number of HMAC-SHA256 bytes kept by LinkageKey; 20
+// bytes encode to exactly 32 base32 characters with no padding.
+const linkageKeyBytes = 20
+
+// LinkageKey validates the CNP and returns a 32-character token, the first 160
+// bits of HMAC-SHA256(salt, cnp) in unpadded base32 (A–Z, 2–7), for use as a
+// short, URL-safe join key between systems that must not exchange the CNP
+// itself. The key is one-way, the same for every call with the same CNP and
+// salt, and collisions are negligible. Two systems produce matching keys only
+// if they use identical salts, which must be kept secret: an attacker with the
+// salt can recover CNPs by enumerating the valid ones. Returns an error if the
+// CNP is invalid.
+func LinkageKey(cnp string, salt []byte) (string, error) {
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(cnp))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil)[:linkageKeyBytes]), nil
+}
@@ -0,0 +1,127 @@
+// ABOUTME: Tests for CNP redaction and generalisation.
+package rossn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMaskWithTag(t *testing.T) {
+	cnp := buildCNP("1", "80", "01", "01", "01", "001")
+	got := MaskWithTag(cnp)
+	sum := sha256.Sum256([]byte(cnp))
+	full := hex.EncodeToString(sum[:])
+	if want := "1************#" + full[len(full)-4:]; got != want {
+		t.Errorf("MaskWithTag(%s) = %s, want %s", cnp, got, want)
+	}
+	if strings.Contains(got, cnp[1:]) {
+		t.Errorf("MaskWithTag leaks the CNP: %s", got)
+	}
+	if MaskWithTag(cnp) != got {
+		t.Errorf("MaskWithTag should be deterministic")
+	}
+	other := buildCNP("1", "80", "01", "01", "01", "002")
+	if MaskWithTag(other) == got {
+		t.Errorf("Different CNPs %s and %s should get different tags", cnp, other)
+	}
+	if got := MaskWithTag(""); !strings.HasPrefix(got, "#") || len(got) != 1+maskTagLength {
+		t.Errorf("MaskWithTag of empty input = %q", got)
+	}
+}
+
+func TestMask_NonASCII(t *testing.T) {
+	cases := map[string]string{
+		"Ș850317401231": "Ș************",
+		"١٨٥٠٣١٧":       "١******",
+		"\xff123":       "\uFFFD***",
+	}
+	for in, want := range cases {
+		got := mask(in)
+		if got != want || !utf8.ValidString(got) {
+			t.Errorf("mask(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := Code("Ș850317401231").LogString(); got != "Ș************" {
+		t.Errorf("LogString of non-ASCII input = %q", got)
+	}
+}
+
+func TestGeneralizeForAnalytics(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		want string
+	}{
+		{buildCNP("1", "80", "01", "01", "01", "001"), "M-1980"},
+		{buildCNP("6", "04", "02", "29", "52", "456"), "F-2004"},
+		{buildCNP("3", "80", "06", "15", "02", "321"), "M-1880"},
+		{buildCNP("8", "99", "12", "31", "46", "789"), "F-1999"},
+		{buildCNP("9", "90", "01", "01", "70", "555"), "U-1990"},
+	}
+	for _, tc := range cases {
+		got, err := GeneralizeForAnalytics(tc.cnp)
+		if err != nil || got != tc.want {
+			t.Errorf("GeneralizeForAnalytics(%s) = %q, %v; want %q", tc.cnp, got, err, tc.want)
+		}
+	}
+	if _, err := GeneralizeForAnalytics("123"); err == nil {
+		t.Errorf("GeneralizeForAnalytics should fail for an invalid CNP")
+	}
+}
+
+func TestSupportView(t *testing.T) {
+	cases := []struct{ cnp, want string }{
+		{buildCNP("1", "80", "05", "17", "01", "123"), "gender M · born 1980 · Alba · ••••"},
+		{buildCNP("6", "04", "02", "29", "40", "001"), "gender F · born 2004 · București · ••••"},
+		{buildCNP("9", "85", "03", "17", "70", "123"), "gender U · born 1985 · Any county (foreign residents, SIIEASC) · ••••"},
+	}
+	for _, tc := range cases {
+		got, err := SupportView(tc.cnp)
+		if err != nil || got != tc.want {
+			t.Errorf("SupportView(%s) = %q, %v; want %q", tc.cnp, got, err, tc.want)
+		}
+		for _, part := range []string{tc.cnp[3:7], tc.cnp[9:]} {
+			if strings.Contains(got, part) {
+				t.Errorf("SupportView(%s) = %q reveals %q", tc.cnp, got, part)
+			}
+		}
+	}
+	if got, err := SupportView("123"); err == nil || got != "" {
+		t.Errorf("SupportView of an invalid CNP = %q, %v; want an error", got, err)
+	}
+}
+
+func TestLinkageKey(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	salt := []byte("warehouse-2025")
+
+	key, err := LinkageKey(a, salt)
+	if err != nil || len(key) != 32 || strings.Trim(key, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567") != "" {
+		t.Fatalf("LinkageKey(%s) = %q, %v; want 32 base32 characters", a, key, err)
+	}
+	// Pinned so keys stay joinable across releases.
+	if key != "XIWG5NEWSZTPKE52DSGYN2ZGJGTAS35P" {
+		t.Errorf("LinkageKey(%s) = %s, want the pinned key", a, key)
+	}
+	if again, _ := LinkageKey(a, salt); again != key {
+		t.Errorf("LinkageKey is not deterministic: %s then %s", key, again)
+	}
+	if other, _ := LinkageKey(b, salt); other == key {
+		t.Errorf("Different CNPs should get different keys")
+	}
+	if other, _ := LinkageKey(a, []byte("other-salt")); other == key {
+		t.Errorf("Different salts should give different keys")
+	}
+	if strings.Contains(key, a) {
+		t.Errorf("The key %s should not contain the CNP", key)
+	}
+
+	for _, bad := range []string{"", "123", a[:12] + string('0'+(a[12]-'0'+1)%10)} {
+		if key, err := LinkageKey(bad, salt); err == nil || key != "" {
+			t.Errorf("LinkageKey(%q) = %q, %v; want an error", bad, key, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Optional counters of validation outcomes by failure reason (WithMetrics).
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Metrics counts validation outcomes by failure reason. Its zero value is ready
+// to use, and it is safe for concurrent use by any number of validators.
+type Metrics struct {
+	valid, length, nonDigit, placeholder, genderDigit atomic.Int64
+	date, county, serial, checksum, other             atomic.Int64
+}
+
+// MetricsSnapshot holds the counter values of a Metrics at one point in time.
+type MetricsSnapshot struct {
+	Valid       int64
+	Length      int64 // ErrInvalidLength
+	NonDigit    int64 // ErrNonDigit
+	Placeholder int64 // ErrPlaceholder
+	GenderDigit int64 // ErrInvalidGenderDigit
+	Date        int64 // ErrInvalidDate
+	County      int64 // ErrInvalidCounty
+	Serial      int64 // ErrInvalidSerial, ErrSerialRejected and ErrSerialTooHigh
+	Checksum    int64 // ErrInvalidControlDigit
+	Other       int64 // policy options and extra checks
+}
+
+// WithMetrics records the outcome of every validation in m. Without this option
+// no counting takes place.
+func WithMetrics(m *Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// counter returns the counter for the outcome err (nil for a valid CNP).
+func (m *Metrics) counter(err error) *atomic.Int64 {
+	switch {
+	case err == nil:
+		return &m.valid
+	case errors.Is(err, ErrInvalidLength):
+		return &m.length
+	case errors.Is(err, ErrNonDigit):
+		return &m.nonDigit
+	case errors.Is(err, ErrPlaceholder):
+		return &m.placeholder
+	case errors.Is(err, ErrInvalidGenderDigit):
+		return &m.genderDigit
+	case errors.Is(err, ErrInvalidDate):
+		return &m.date
+	case errors.Is(err, ErrInvalidCounty):
+		return &m.county
+	case errors.Is(err, ErrInvalidSerial), errors.Is(err, ErrSerialRejected), errors.Is(err, ErrSerialTooHigh):
+		return &m.serial
+	case errors.Is(err, ErrInvalidControlDigit):
+		return &m.checksum
+	default:
+		return &m.other
+	}
+}
+
+// record counts one validation outcome.
+func (m *Metrics) record(err error) {
+	m.counter(err).Add(1)
+}
+
+// Snapshot returns the current counter values.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return m.collect((*atomic.Int64).Load)
+}
+
+// Reset zeroes every counter and returns the values it held, so a service can
+// report per-interval totals. Each counter is swapped atomically; a validation
+// running concurrently is counted in either the returned or the next interval.
+func (m *Metrics) Reset() MetricsSnapshot {
+	return m.collect(func(c *atomic.Int64) int64 { return c.Swap(0) })
+}
+
+func (m *Metrics) collect(read func(*atomic.Int64) int64) MetricsSnapshot {
+	return MetricsSnapshot{
+		Valid:       read(&m.valid),
+		Length:      read(&m.length),
+		NonDigit:    read(&m.nonDigit),
+		Placeholder: read(&m.placeholder),
+		GenderDigit: read(&m.genderDigit),
+		Date:        read(&m.date),
+		County:      read(&m.county),
+		Serial:      read(&m.serial),
+		Checksum:    read(&m.checksum),
+		Other:       read(&m.other),
+	}
+}
@@ -0,0 +1,82 @@
+// ABOUTME: Tests for validation metrics counters.
+package rossn
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	var m Metrics
+	v := NewValidator(WithMetrics(&m), WithExtraCheck(func(cnp string) error {
+		if cnp[9:12] == "777" {
+			return errors.New("reserved serial")
+		}
+		return nil
+	}))
+	inputs := []string{
+		buildCNP("1", "80", "01", "01", "01", "001"),
+		buildCNP("2", "95", "12", "15", "12", "123"),
+		"123",
+		"12345678901ab",
+		"0000000000000",
+		buildCNP("0", "80", "01", "01", "01", "001"),
+		buildCNP("1", "80", "02", "30", "01", "001"),
+		buildCNP("1", "80", "01", "01", "49", "001"),
+		buildCNP("1", "80", "01", "01", "01", "000"),
+		buildCNP("1", "80", "01", "01", "01", "001")[:12] + "0",
+		buildCNP("1", "80", "01", "01", "01", "777"),
+	}
+	for _, cnp := range inputs {
+		v.Validate(cnp)
+	}
+	want := MetricsSnapshot{
+		Valid: 2, Length: 1, NonDigit: 1, Placeholder: 1, GenderDigit: 1,
+		Date: 1, County: 1, Serial: 1, Checksum: 1, Other: 1,
+	}
+	if got := m.Snapshot(); got != want {
+		t.Errorf("Snapshot() = %+v, want %+v", got, want)
+	}
+	if got := m.Reset(); got != want {
+		t.Errorf("Reset() = %+v, want %+v", got, want)
+	}
+	if got := m.Snapshot(); got != (MetricsSnapshot{}) {
+		t.Errorf("Counters should be zero after Reset, got %+v", got)
+	}
+}
+
+func TestWithMetrics_ValidatorAgeMethods(t *testing.T) {
+	var m Metrics
+	v := NewValidator(WithMetrics(&m))
+	valid := buildCNP("1", "80", "01", "01", "01", "001")
+	v.Age(valid)
+	v.Age("123")
+	v.DaysUntilBirthday(valid)
+	v.DaysUntilBirthday(buildCNP("1", "80", "01", "01", "49", "001"))
+	want := MetricsSnapshot{Valid: 2, Length: 1, County: 1}
+	if got := m.Snapshot(); got != want {
+		t.Errorf("Snapshot() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithMetrics_Concurrent(t *testing.T) {
+	var m Metrics
+	v := NewValidator(WithMetrics(&m))
+	valid := Generate()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				v.Validate(valid)
+				v.Validate("bad")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := m.Snapshot(); got.Valid != 4000 || got.Length != 4000 {
+		t.Errorf("Concurrent counts = %+v, want 4000 valid and 4000 length failures", got)
+	}
+}
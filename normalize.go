@@ -0,0 +1,79 @@
+// ABOUTME: Normalisation of CNP input to the canonical ASCII form.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NormalizeDigits converts every decimal digit rune in cnp (any rune for which
+// unicode.IsDigit reports true, such as full-width or Arabic-Indic digits) to its
+// ASCII equivalent 0–9, then validates the result and returns it. Input that is
+// already ASCII is returned unchanged once it validates, so stored CNPs are
+// always ASCII regardless of the input source.
+func NormalizeDigits(cnp string) (string, error) {
+	var b strings.Builder
+	b.Grow(cnpLength)
+	for _, r := range cnp {
+		if v, ok := digitValue(r); ok {
+			b.WriteByte(byte('0' + v))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	ascii := b.String()
+	if err := Validate(ascii); err != nil {
+		return "", err
+	}
+	return ascii, nil
+}
+
+// ValidateLenient accepts CNPs typed or pasted with non-ASCII decimal digits,
+// such as full-width "１２３" or Arabic-Indic "١٢٣", which Validate rejects. It
+// maps every Unicode decimal digit to ASCII, validates the result and returns
+// the ASCII canonical form; it is the same operation as NormalizeDigits.
+func ValidateLenient(cnp string) (string, error) {
+	return NormalizeDigits(cnp)
+}
+
+// ValidateFlexibleLength accepts CNPs with or without the control digit, for
+// importers receiving both forms. A 13-character input is validated as by
+// Validate and returned unchanged; for 12 ASCII digits the control digit is
+// computed and appended, and the 13-digit result is validated and returned.
+// Other lengths fail with ErrInvalidLength. Validate itself stays strict.
+func ValidateFlexibleLength(cnp string) (string, error) {
+	switch len(cnp) {
+	case cnpLength:
+	case cnpLength - 1:
+		if !isStructural(cnp + "0") {
+			return "", ErrNonDigit
+		}
+		cnp += strconv.Itoa(controlDigit(cnp))
+	default:
+		return "", ErrInvalidLength
+	}
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return cnp, nil
+}
+
+// digitValue returns the numeric value of a Unicode decimal digit. Decimal
+// digits are encoded in contiguous runs starting at zero, so the value is the
+// rune's distance from the start of its run, modulo 10 for runs of several sets.
+func digitValue(r rune) (int, bool) {
+	if r >= '0' && r <= '9' {
+		return int(r - '0'), true
+	}
+	if !unicode.IsDigit(r) {
+		return 0, false
+	}
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+	return int(r-start) % 10, true
+}
@@ -0,0 +1,105 @@
+// ABOUTME: Tests for normalising non-ASCII digits in CNP input.
+package rossn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// toScript rewrites the ASCII digits of s using the digit zero of another script.
+func toScript(s string, zero rune) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return zero + (r - '0')
+		}
+		return r
+	}, s)
+}
+
+func TestNormalizeDigits(t *testing.T) {
+	cnp := buildCNP("1", "80", "01", "01", "12", "345")
+	inputs := []string{
+		cnp,
+		toScript(cnp, '０'),          // full-width
+		toScript(cnp, '٠'),          // Arabic-Indic
+		toScript(cnp, '۰'),          // Extended Arabic-Indic (Persian)
+		toScript(cnp, '०'),          // Devanagari
+		toScript(cnp, '\U0001D7CE'), // Mathematical bold
+		toScript(cnp[:6], '０') + cnp[6:],
+	}
+	for _, in := range inputs {
+		got, err := NormalizeDigits(in)
+		if err != nil || got != cnp {
+			t.Errorf("NormalizeDigits(%q) = %q, %v; want %q", in, got, err, cnp)
+		}
+	}
+
+	// Mathematical sans-serif digits follow the bold set directly; values must still wrap.
+	if v, ok := digitValue('\U0001D7E2' + 7); !ok || v != 7 {
+		t.Errorf("digitValue for sans-serif 7 = %d, %v", v, ok)
+	}
+
+	for _, in := range []string{"123", toScript(cnp[:12], '０') + "9", "198001011234x"} {
+		if _, err := NormalizeDigits(in); err == nil {
+			t.Errorf("NormalizeDigits(%q) should fail", in)
+		}
+	}
+}
+
+func TestValidateLenient(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for _, in := range []string{toScript(cnp, '０'), toScript(cnp, '٠')} {
+		if err := Validate(in); err == nil {
+			t.Errorf("Strict Validate should reject %q", in)
+		}
+		got, err := ValidateLenient(in)
+		if err != nil || got != cnp {
+			t.Errorf("ValidateLenient(%q) = %q, %v; want %q", in, got, err, cnp)
+		}
+	}
+
+	// A 13-byte string of digits that is not all ASCII fails the strict digit check.
+	mixed := toScript(cnp[:1], '٠') + cnp[2:]
+	if len(mixed) != cnpLength {
+		t.Fatalf("Test setup: %q is %d bytes", mixed, len(mixed))
+	}
+	if err := Validate(mixed); !errors.Is(err, ErrNonDigit) {
+		t.Errorf("Validate(%q) = %v, want ErrNonDigit", mixed, err)
+	}
+
+	if _, err := ValidateLenient(toScript(cnp[:12], '０') + "x"); err == nil {
+		t.Errorf("ValidateLenient should still reject non-digits")
+	}
+}
+
+func TestValidateFlexibleLength(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for _, in := range []string{cnp, cnp[:12]} {
+		if got, err := ValidateFlexibleLength(in); err != nil || got != cnp {
+			t.Errorf("ValidateFlexibleLength(%s) = %q, %v; want %s", in, got, err, cnp)
+		}
+	}
+
+	badControl := cnp[:12] + string('0'+(cnp[12]-'0'+1)%10)
+	cases := []struct {
+		in   string
+		want error
+	}{
+		{badControl, ErrInvalidControlDigit},
+		{cnp[:11], ErrInvalidLength},
+		{cnp + "0", ErrInvalidLength},
+		{"", ErrInvalidLength},
+		{"28503174012x", ErrNonDigit},
+		{"285023040123", ErrInvalidDate},
+		{"000000000000", ErrPlaceholder}, // completes to 0000000000000
+	}
+	for _, tc := range cases {
+		if got, err := ValidateFlexibleLength(tc.in); !errors.Is(err, tc.want) || got != "" {
+			t.Errorf("ValidateFlexibleLength(%q) = %q, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+	if err := Validate(cnp[:12]); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Validate should stay strict about the length, got %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+// ABOUTME: Configurable validation through functional options (ValidateWith).
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPlaceholder is returned when the input is a well-known dummy value
+// (such as "0000000000000" or "1234567890123") rather than a real CNP.
+var ErrPlaceholder = errors.New("CNP is a placeholder value")
+
+// ErrBirthYearTooEarly is returned when a CNP's birth year is below the MinBirthYear bound.
+var ErrBirthYearTooEarly = errors.New("birth year is before the minimum allowed")
+
+// ErrBirthYearOutOfRange is returned when a CNP's birth year is outside the
+// window set with BirthYearRange.
+var ErrBirthYearOutOfRange = errors.New("birth year is outside the allowed range")
+
+// ErrSuspiciousDate is returned when a CNP's birth date is one of the dates
+// configured with RejectSuspiciousDates.
+var ErrSuspiciousDate = errors.New("birth date is a commonly fabricated value")
+
+// ErrSerialRejected is returned when a CNP's serial is in the standard 1–999
+// range but fails the predicate given to WithSerialValidator.
+var ErrSerialRejected = errors.New("serial number rejected by custom rule")
+
+// ErrSerialTooHigh is returned when a CNP's serial exceeds the maximum set with
+// WithMaxIssuedSerial in its hard-failure mode.
+var ErrSerialTooHigh = errors.New("serial number is above the maximum issued")
+
+// ErrBlocked is returned when a structurally valid CNP is in the set given to WithBlocklist.
+var ErrBlocked = errors.New("CNP is blocked")
+
+// Clock supplies the current time to time-dependent validation rules.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock, reading the system time.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Option adjusts the rules applied by ValidateWith.
+type Option func(*config)
+
+// config holds the effective validation rules.
+type config struct {
+	placeholders  map[string]struct{}
+	warnArchival  func(cnp string)
+	minBirthYear  int
+	yearRange     *[2]int // inclusive [min, max] birth years, nil for no window
+	extraChecks   []func(cnp string) error
+	serialOK      func(serial int) bool
+	maxSerial     int  // highest accepted serial, 0 for no limit
+	maxSerialWarn bool // report serials above maxSerial as a warning only
+	siieasc       bool
+	siieascAnyS   bool // apply SIIEASC decoding to every S digit, not just 7–9
+	siieascRef    int  // reference year for SIIEASC decoding, 0 for the clock
+	metrics       *Metrics
+	clock         Clock
+	blocklist     map[string]struct{}
+
+	suspiciousDates map[int]struct{} // birth dates as YYYYMMDD
+}
+
+// defaultConfig is the configuration used by Validate.
+var defaultConfig = newConfig(nil)
+
+// defaultPlaceholders lists the dummy values rejected by default:
+// every all-same-digit string and the ascending "1234567890123".
+var defaultPlaceholders = []string{
+	"0000000000000", "1111111111111", "2222222222222", "3333333333333",
+	"4444444444444", "5555555555555", "6666666666666", "7777777777777",
+	"8888888888888", "9999999999999", "1234567890123",
+}
+
+// defaultPlaceholderSet is the set form of defaultPlaceholders, built once and
+// shared read-only by every configuration that keeps the default placeholders.
+var defaultPlaceholderSet = func() map[string]struct{} {
+	c := &config{}
+	Placeholders(defaultPlaceholders...)(c)
+	return c.placeholders
+}()
+
+// newConfig returns the default configuration with opts applied in order.
+// Options build any state they need, such as lookup sets, at this point, so
+// validating under the returned configuration does no setup work.
+func newConfig(opts []Option) *config {
+	c := &config{placeholders: defaultPlaceholderSet, clock: realClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ValidateWith checks a CNP like Validate, with the rules adjusted by opts.
+// With no options it behaves exactly like Validate.
+func ValidateWith(cnp string, opts ...Option) error {
+	return newConfig(opts).validate(cnp)
+}
+
+// Placeholders replaces the set of values rejected with ErrPlaceholder.
+// Calling it with no arguments disables placeholder detection.
+func Placeholders(values ...string) Option {
+	return func(c *config) {
+		c.placeholders = make(map[string]struct{}, len(values))
+		for _, v := range values {
+			c.placeholders[v] = struct{}{}
+		}
+	}
+}
+
+// WarnArchival accepts historic Bucharest codes 47/48 carrying a birth date on or
+// after the 1979-12-19 cutoff, which are otherwise rejected as an invalid county.
+// Such CNPs pass validation if every other rule holds, and fn is called with each
+// one so it can be flagged. fn is not called for CNPs that fail validation.
+func WarnArchival(fn func(cnp string)) Option {
+	return func(c *config) {
+		c.warnArchival = fn
+	}
+}
+
+// MinBirthYear rejects otherwise valid CNPs whose birth year is below year,
+// returning ErrBirthYearTooEarly. The year includes the century from the S digit,
+// so an S=3 (18xx) CNP fails MinBirthYear(1900). By default there is no bound.
+func MinBirthYear(year int) Option {
+	return func(c *config) {
+		c.minBirthYear = year
+	}
+}
+
+// BirthYearRange rejects otherwise valid CNPs whose birth year is outside
+// [min, max], returning ErrBirthYearOutOfRange. The year includes the century
+// from the S digit, so cohorts spanning 1899–1901 compare correctly. It restricts
+// calendar years, not ages. By default there is no window.
+func BirthYearRange(min, max int) Option {
+	return func(c *config) {
+		c.yearRange = &[2]int{min, max}
+	}
+}
+
+// defaultSuspiciousDates are the over-used fake birth dates checked by
+// RejectSuspiciousDates when called without arguments.
+var defaultSuspiciousDates = []time.Time{
+	time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// RejectSuspiciousDates rejects otherwise valid CNPs whose birth date equals one
+// of dates (compared by calendar date), returning ErrSuspiciousDate. With no
+// arguments it uses the over-used fake dates 1900-01-01 and 1970-01-01.
+// By default no date is rejected.
+func RejectSuspiciousDates(dates ...time.Time) Option {
+	if len(dates) == 0 {
+		dates = defaultSuspiciousDates
+	}
+	return func(c *config) {
+		c.suspiciousDates = make(map[int]struct{}, len(dates))
+		for _, d := range dates {
+			y, m, day := d.Date()
+			c.suspiciousDates[y*10000+int(m)*100+day] = struct{}{}
+		}
+	}
+}
+
+// WithSerialValidator narrows the accepted serials (NNN) to those for which fn
+// returns true, for institutions that reserve or exclude certain values. Serials
+// outside 1–999 still fail with ErrInvalidSerial; those rejected by fn fail with
+// ErrSerialRejected. By default every serial in 1–999 is accepted.
+func WithSerialValidator(fn func(serial int) bool) Option {
+	return func(c *config) {
+		c.serialOK = fn
+	}
+}
+
+// WithMaxIssuedSerial flags CNPs whose serial (NNN) exceeds max, for
+// institutions that never issue higher serials and treat them as a sign of a
+// fabricated number. Unless warnOnly is set they fail with ErrSerialTooHigh;
+// with it they pass, and ValidateWithWarnings reports WarningSerialTooHigh.
+// A max below 1 sets no limit, which is the default.
+func WithMaxIssuedSerial(max int, warnOnly bool) Option {
+	return func(c *config) {
+		c.maxSerial = max
+		c.maxSerialWarn = warnOnly
+	}
+}
+
+// serialTooHigh reports whether serial exceeds the configured maximum.
+func (c *config) serialTooHigh(serial int) bool {
+	return c.maxSerial > 0 && serial > c.maxSerial
+}
+
+// SIIEASCMode decodes county-70 CNPs with S=7, 8 or 9 as SIIEASC numbers when
+// 20YY is between 2024 and the current year (see WithClock), instead of forcing
+// 19YY as the S digit otherwise implies. The year matters to rules that read it, such as
+// MinBirthYear and BirthYearRange: by default a foreign resident's 2024 SIIEASC
+// CNP decodes to 1924 and fails MinBirthYear(2000); in this mode it passes.
+func SIIEASCMode() Option {
+	return func(c *config) {
+		c.siieasc = true
+	}
+}
+
+// ModernSIIEASC decodes every county-70 CNP, whatever its S digit, as a SIIEASC
+// number born in 20YY when that year is between 2024 and referenceYear, so that
+// YY up to referenceYear mod 100 takes the 2000 base. Such CNPs then satisfy the
+// 2024+ county-70 rule even with a citizen S digit such as 1 or 2, which by
+// default decodes to 19YY and fails with ErrInvalidCounty. Unlike SIIEASCMode,
+// the reference year is fixed rather than read from the clock. It overrides
+// SIIEASCMode when both are given.
+func ModernSIIEASC(referenceYear int) Option {
+	return func(c *config) {
+		c.siieasc = true
+		c.siieascAnyS = true
+		c.siieascRef = referenceYear
+	}
+}
+
+// siieascYear returns the SIIEASC birth year for a county-70 CNP if 2000+YY
+// lies in [2024, current], and year unchanged otherwise. Unless anyS is set,
+// only S=7–9 are decoded this way.
+func siieascYear(f cnpFields, year, current int, anyS bool) int {
+	if f.jj != "70" || (!anyS && (f.s < SForeignMale || f.s > SNonResident)) {
+		return year
+	}
+	if y := 2000 + year%100; y >= siieascStartYear && y <= current {
+		return y
+	}
+	return year
+}
+
+// WithBlocklist rejects CNPs in set, such as revoked numbers, with ErrBlocked.
+// The lookup runs only once a CNP has passed every format check, including the
+// control digit, so malformed input is never looked up. The set is used as is,
+// not copied: concurrent validations may share it, but it must not be modified
+// while they run.
+func WithBlocklist(set map[string]struct{}) Option {
+	return func(c *config) {
+		c.blocklist = set
+	}
+}
+
+// WithExtraCheck adds a caller-defined rule, such as an institution's own
+// secondary check, that runs after all standard checks have passed. A non-nil
+// error from fn is returned unchanged. Several extra checks run in the order given.
+func WithExtraCheck(fn func(cnp string) error) Option {
+	return func(c *config) {
+		c.extraChecks = append(c.extraChecks, fn)
+	}
+}
+
+// WithClock makes time-dependent rules, such as the reference year of
+// SIIEASCMode and the Validator's Age and DaysUntilBirthday, read the current
+// time from clk instead of the system clock, so they can be tested
+// deterministically (see rossntest.FakeClock). A nil clk means the system clock.
+func WithClock(clk Clock) Option {
+	return func(c *config) {
+		if clk == nil {
+			clk = realClock{}
+		}
+		c.clock = clk
+	}
+}
+
+// Validator applies a fixed set of options to every CNP it checks.
+// It is safe for concurrent use.
+type Validator struct {
+	cfg *config
+}
+
+// NewValidator returns a Validator applying opts, equivalent to calling
+// ValidateWith with the same options on every CNP. The configuration is built
+// once, here; construct a Validator at startup rather than calling ValidateWith
+// with options in a hot loop (see BenchmarkValidator_Validate).
+func NewValidator(opts ...Option) *Validator {
+	return &Validator{cfg: newConfig(opts)}
+}
+
+// Validate checks the CNP under the Validator's options.
+func (v *Validator) Validate(cnp string) error {
+	return v.cfg.validate(cnp)
+}
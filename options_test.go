@@ -0,0 +1,433 @@
+// ABOUTME: Tests for ValidateWith and its options.
+package rossn
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateWith_NoOptionsMatchesValidate(t *testing.T) {
+	inputs := []string{
+		buildCNP("1", "80", "01", "01", "01", "001"),
+		buildCNP("1", "80", "02", "30", "01", "001"),
+		"0000000000000",
+		"123",
+	}
+	for _, cnp := range inputs {
+		if got, want := ValidateWith(cnp), Validate(cnp); (got == nil) != (want == nil) {
+			t.Errorf("ValidateWith(%s)=%v but Validate=%v", cnp, got, want)
+		}
+	}
+}
+
+func TestValidate_Placeholders(t *testing.T) {
+	placeholders := []string{
+		"0000000000000", "1111111111111", "5555555555555",
+		"9999999999999", "1234567890123",
+	}
+	for _, cnp := range placeholders {
+		if err := Validate(cnp); !errors.Is(err, ErrPlaceholder) {
+			t.Errorf("Placeholder %s should fail with ErrPlaceholder, got %v", cnp, err)
+		}
+	}
+
+	// A genuine typo is not reported as a placeholder.
+	typo := buildCNP("1", "80", "01", "01", "01", "001")[:12] + "9"
+	if err := Validate(typo); err == nil || errors.Is(err, ErrPlaceholder) {
+		t.Errorf("Typo %s should fail without ErrPlaceholder, got %v", typo, err)
+	}
+}
+
+func TestValidateWith_CustomPlaceholders(t *testing.T) {
+	valid := buildCNP("1", "80", "01", "01", "01", "001")
+	if err := ValidateWith(valid, Placeholders(valid)); !errors.Is(err, ErrPlaceholder) {
+		t.Errorf("Custom placeholder %s should fail with ErrPlaceholder, got %v", valid, err)
+	}
+
+	// Replacing the set drops the defaults; they still fail, just not as placeholders.
+	err := ValidateWith("0000000000000", Placeholders(valid))
+	if err == nil || errors.Is(err, ErrPlaceholder) {
+		t.Errorf("Default placeholder should no longer be reported as such, got %v", err)
+	}
+	if err := ValidateWith("1111111111111", Placeholders()); errors.Is(err, ErrPlaceholder) {
+		t.Errorf("Placeholders() should disable detection, got %v", err)
+	}
+}
+
+func TestValidateWith_WarnArchival(t *testing.T) {
+	var warned []string
+	warn := WarnArchival(func(cnp string) { warned = append(warned, cnp) })
+
+	late := buildCNP("1", "85", "03", "10", "47", "123") // 1985, after the cutoff
+	if err := Validate(late); err == nil {
+		t.Fatalf("Post-cutoff JJ=47 should fail by default: %s", late)
+	}
+	if err := ValidateWith(late, warn); err != nil {
+		t.Errorf("Post-cutoff JJ=47 should pass with WarnArchival: %s, err=%v", late, err)
+	}
+	if len(warned) != 1 || warned[0] != late {
+		t.Errorf("Expected one warning for %s, got %v", late, warned)
+	}
+
+	// In-window archival codes and ordinary codes do not warn.
+	warned = nil
+	early := buildCNP("2", "78", "05", "10", "48", "555")
+	normal := buildCNP("1", "85", "03", "10", "12", "123")
+	for _, cnp := range []string{early, normal} {
+		if err := ValidateWith(cnp, warn); err != nil {
+			t.Errorf("CNP should pass: %s, err=%v", cnp, err)
+		}
+	}
+	if len(warned) != 0 {
+		t.Errorf("No warnings expected, got %v", warned)
+	}
+
+	// Other failures are still reported and do not warn.
+	badChecksum := late[:12] + string('0'+(late[12]-'0'+1)%10)
+	if err := ValidateWith(badChecksum, warn); err == nil {
+		t.Errorf("Bad checksum should still fail: %s", badChecksum)
+	}
+	badCounty := buildCNP("1", "85", "03", "10", "49", "123")
+	if err := ValidateWith(badCounty, warn); err == nil {
+		t.Errorf("JJ=49 should still fail: %s", badCounty)
+	}
+	if len(warned) != 0 {
+		t.Errorf("Failed CNPs should not warn, got %v", warned)
+	}
+}
+
+func TestValidateWith_MinBirthYear(t *testing.T) {
+	cases := []struct {
+		cnp  string
+		min  int
+		pass bool
+	}{
+		{buildCNP("1", "95", "06", "15", "12", "123"), 1990, true},
+		{buildCNP("1", "90", "01", "01", "12", "123"), 1990, true},
+		{buildCNP("1", "89", "12", "31", "12", "123"), 1990, false},
+		{buildCNP("3", "99", "12", "31", "12", "123"), 1900, false}, // 1899 despite YY=99
+		{buildCNP("5", "01", "01", "01", "12", "123"), 1990, true},  // 2001 despite YY=01
+	}
+	for _, tc := range cases {
+		err := ValidateWith(tc.cnp, MinBirthYear(tc.min))
+		if tc.pass && err != nil {
+			t.Errorf("CNP %s should pass MinBirthYear(%d), got %v", tc.cnp, tc.min, err)
+		}
+		if !tc.pass && !errors.Is(err, ErrBirthYearTooEarly) {
+			t.Errorf("CNP %s should fail MinBirthYear(%d) with ErrBirthYearTooEarly, got %v", tc.cnp, tc.min, err)
+		}
+	}
+
+	// Structural failures take precedence over the policy bound.
+	if err := ValidateWith("123", MinBirthYear(1990)); err == nil || errors.Is(err, ErrBirthYearTooEarly) {
+		t.Errorf("Malformed input should report a structural error, got %v", err)
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	late := buildCNP("1", "85", "03", "10", "47", "123")
+	v := NewValidator(WarnArchival(func(string) {}), MinBirthYear(1980))
+	if err := v.Validate(late); err != nil {
+		t.Errorf("Validator should apply WarnArchival: %v", err)
+	}
+	if err := v.Validate(buildCNP("1", "75", "03", "10", "12", "123")); !errors.Is(err, ErrBirthYearTooEarly) {
+		t.Errorf("Validator should apply MinBirthYear, got %v", err)
+	}
+	if err := NewValidator().Validate(late); !errors.Is(err, ErrInvalidCounty) {
+		t.Errorf("Validator without options should behave like Validate, got %v", err)
+	}
+}
+
+func TestValidateWith_ExtraCheck(t *testing.T) {
+	errOdd := errors.New("serial must be even")
+	evenSerial := WithExtraCheck(func(cnp string) error {
+		if (cnp[11]-'0')%2 != 0 {
+			return errOdd
+		}
+		return nil
+	})
+
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "002"), evenSerial); err != nil {
+		t.Errorf("Even serial should pass the extra check, got %v", err)
+	}
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "001"), evenSerial); !errors.Is(err, errOdd) {
+		t.Errorf("Odd serial should fail the extra check, got %v", err)
+	}
+
+	// The extra check only runs once the standard checks pass.
+	called := false
+	spy := WithExtraCheck(func(string) error { called = true; return nil })
+	if err := ValidateWith("0000000000002", spy); err == nil {
+		t.Errorf("Invalid CNP should fail before the extra check")
+	}
+	if called {
+		t.Errorf("Extra check should not run for an invalid CNP")
+	}
+
+	// Checks run in order and stop at the first failure.
+	var order []int
+	first := WithExtraCheck(func(string) error { order = append(order, 1); return errOdd })
+	second := WithExtraCheck(func(string) error { order = append(order, 2); return nil })
+	ValidateWith(buildCNP("1", "80", "01", "01", "01", "002"), first, second)
+	if len(order) != 1 || order[0] != 1 {
+		t.Errorf("Expected only the first check to run, got %v", order)
+	}
+}
+
+func TestValidateWith_RejectSuspiciousDates(t *testing.T) {
+	fake1900 := buildCNP("9", "00", "01", "01", "70", "555")
+	fake1970 := buildCNP("1", "70", "01", "01", "12", "123")
+	real := buildCNP("1", "70", "01", "02", "12", "123")
+
+	for _, cnp := range []string{fake1900, fake1970, real} {
+		if err := Validate(cnp); err != nil {
+			t.Errorf("Suspicious dates must not fail by default: %s, err=%v", cnp, err)
+		}
+	}
+	for _, cnp := range []string{fake1900, fake1970} {
+		if err := ValidateWith(cnp, RejectSuspiciousDates()); !errors.Is(err, ErrSuspiciousDate) {
+			t.Errorf("Default suspicious date should fail: %s, got %v", cnp, err)
+		}
+	}
+	if err := ValidateWith(real, RejectSuspiciousDates()); err != nil {
+		t.Errorf("Ordinary date should pass: %s, got %v", real, err)
+	}
+
+	custom := RejectSuspiciousDates(time.Date(1970, 1, 2, 15, 30, 0, 0, time.UTC))
+	if err := ValidateWith(real, custom); !errors.Is(err, ErrSuspiciousDate) {
+		t.Errorf("Custom suspicious date should fail regardless of time of day: %s, got %v", real, err)
+	}
+	if err := ValidateWith(fake1970, custom); err != nil {
+		t.Errorf("A custom list replaces the defaults: %s, got %v", fake1970, err)
+	}
+}
+
+func TestValidateWith_MaxIssuedSerial(t *testing.T) {
+	at500 := buildCNP("1", "80", "01", "01", "01", "500")
+	at501 := buildCNP("1", "80", "01", "01", "01", "501")
+
+	if err := ValidateWith(at500, WithMaxIssuedSerial(500, false)); err != nil {
+		t.Errorf("Serial 500 should pass a maximum of 500, got %v", err)
+	}
+	if err := ValidateWith(at501, WithMaxIssuedSerial(500, false)); !errors.Is(err, ErrSerialTooHigh) {
+		t.Errorf("Serial 501 should fail a maximum of 500, got %v", err)
+	}
+	if err := ValidateWith(at501, WithMaxIssuedSerial(500, true)); err != nil {
+		t.Errorf("In warning mode serial 501 should pass, got %v", err)
+	}
+	for _, max := range []int{0, -1} {
+		if err := ValidateWith(at501, WithMaxIssuedSerial(max, false)); err != nil {
+			t.Errorf("WithMaxIssuedSerial(%d) should set no limit, got %v", max, err)
+		}
+	}
+	// Earlier checks keep their own errors.
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "000"), WithMaxIssuedSerial(500, false)); !errors.Is(err, ErrInvalidSerial) {
+		t.Errorf("Serial 000 should still fail with ErrInvalidSerial, got %v", err)
+	}
+
+	warnings, err := ValidateWithWarnings(at501, WithMaxIssuedSerial(500, true))
+	if err != nil || len(warnings) != 1 || warnings[0].Code != WarningSerialTooHigh {
+		t.Errorf("ValidateWithWarnings = %v, %v; want one %s warning", warnings, err, WarningSerialTooHigh)
+	}
+	if warnings, _ := ValidateWithWarnings(at500, WithMaxIssuedSerial(500, true)); len(warnings) != 0 {
+		t.Errorf("Serial 500 should not warn, got %v", warnings)
+	}
+
+	var m Metrics
+	_ = ValidateWith(at501, WithMaxIssuedSerial(500, false), WithMetrics(&m))
+	if s := m.Snapshot(); s.Serial != 1 {
+		t.Errorf("ErrSerialTooHigh should be counted as a serial failure, got %+v", s)
+	}
+}
+
+func TestValidateWith_SerialValidator(t *testing.T) {
+	not500 := WithSerialValidator(func(serial int) bool { return serial != 500 })
+
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "499"), not500); err != nil {
+		t.Errorf("Serial 499 should pass, got %v", err)
+	}
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "500"), not500); !errors.Is(err, ErrSerialRejected) {
+		t.Errorf("Serial 500 should be rejected by the custom rule, got %v", err)
+	}
+	if err := Validate(buildCNP("1", "80", "01", "01", "01", "500")); err != nil {
+		t.Errorf("Serial 500 should pass by default, got %v", err)
+	}
+
+	// The standard range check still applies and keeps its own error.
+	all := WithSerialValidator(func(int) bool { return true })
+	if err := ValidateWith(buildCNP("1", "80", "01", "01", "01", "000"), all); !errors.Is(err, ErrInvalidSerial) {
+		t.Errorf("Serial 000 should fail the range check, got %v", err)
+	}
+}
+
+func TestValidateWith_BirthYearRange(t *testing.T) {
+	cohort := BirthYearRange(1899, 1901)
+	cases := []struct {
+		cnp  string
+		pass bool
+	}{
+		{buildCNP("3", "99", "12", "31", "12", "123"), true},  // 1899
+		{buildCNP("1", "00", "01", "01", "12", "123"), true},  // 1900
+		{buildCNP("2", "01", "12", "31", "12", "123"), true},  // 1901
+		{buildCNP("3", "98", "12", "31", "12", "123"), false}, // 1898
+		{buildCNP("1", "02", "01", "01", "12", "123"), false}, // 1902
+		{buildCNP("5", "01", "01", "01", "12", "123"), false}, // 2001 despite YY=01
+	}
+	for _, tc := range cases {
+		err := ValidateWith(tc.cnp, cohort)
+		if tc.pass && err != nil {
+			t.Errorf("CNP %s should be inside the cohort, got %v", tc.cnp, err)
+		}
+		if !tc.pass && !errors.Is(err, ErrBirthYearOutOfRange) {
+			t.Errorf("CNP %s should fail with ErrBirthYearOutOfRange, got %v", tc.cnp, err)
+		}
+	}
+	if err := Validate(buildCNP("5", "01", "01", "01", "12", "123")); err != nil {
+		t.Errorf("There should be no year window by default, got %v", err)
+	}
+}
+
+func TestValidateWith_SIIEASCMode(t *testing.T) {
+	// A foreign resident's SIIEASC CNP from 2024. The S digit implies 19xx, so by
+	// default it decodes to 1924: it passes Validate but fails any bound that
+	// reads the year.
+	siieasc := buildCNP("7", "24", "05", "10", "70", "123")
+	if err := Validate(siieasc); err != nil {
+		t.Errorf("SIIEASC CNP should pass Validate, got %v", err)
+	}
+	if err := ValidateWith(siieasc, MinBirthYear(2000)); !errors.Is(err, ErrBirthYearTooEarly) {
+		t.Errorf("By default the CNP decodes to 1924 and should fail MinBirthYear(2000), got %v", err)
+	}
+	if err := ValidateWith(siieasc, SIIEASCMode(), MinBirthYear(2000)); err != nil {
+		t.Errorf("In SIIEASC mode the CNP decodes to 2024 and should pass, got %v", err)
+	}
+	if err := ValidateWith(siieasc, SIIEASCMode(), BirthYearRange(2024, 2024)); err != nil {
+		t.Errorf("In SIIEASC mode the birth year should be 2024, got %v", err)
+	}
+
+	// Other counties, citizen S digits and pre-2024 years are unaffected.
+	for _, cnp := range []string{
+		buildCNP("7", "24", "05", "10", "12", "123"),
+		buildCNP("7", "23", "05", "10", "70", "123"),
+		buildCNP("7", "85", "05", "10", "70", "123"),
+	} {
+		if err := ValidateWith(cnp, SIIEASCMode(), BirthYearRange(1900, 1999)); err != nil {
+			t.Errorf("CNP %s should still decode to 19xx, got %v", cnp, err)
+		}
+	}
+}
+
+func TestValidateWith_ModernSIIEASC(t *testing.T) {
+	// 2024–2025 SIIEASC CNPs whose S digit decodes to 18xx or 19xx by default,
+	// making county 70 invalid for citizens.
+	modernOnly := []string{
+		buildCNP("1", "24", "03", "01", "70", "001"),
+		buildCNP("2", "24", "12", "31", "70", "042"),
+		buildCNP("3", "25", "06", "15", "70", "007"),
+		buildCNP("4", "25", "01", "01", "70", "999"),
+	}
+	for _, cnp := range modernOnly {
+		if err := Validate(cnp); !errors.Is(err, ErrInvalidCounty) {
+			t.Errorf("Validate(%s) = %v, want ErrInvalidCounty", cnp, err)
+		}
+		if err := ValidateWith(cnp, SIIEASCMode()); !errors.Is(err, ErrInvalidCounty) {
+			t.Errorf("SIIEASCMode should still reject %s, got %v", cnp, err)
+		}
+		if err := ValidateWith(cnp, ModernSIIEASC(2025), BirthYearRange(2024, 2025)); err != nil {
+			t.Errorf("ModernSIIEASC(2025) should accept %s as born 2024–2025, got %v", cnp, err)
+		}
+	}
+
+	// S=5–9 already validate; the mode only moves 7–9 into 20YY.
+	for _, cnp := range []string{
+		buildCNP("5", "24", "03", "01", "70", "001"),
+		buildCNP("6", "25", "03", "01", "70", "001"),
+		buildCNP("7", "24", "03", "01", "70", "001"),
+		buildCNP("9", "25", "03", "01", "70", "001"),
+	} {
+		if err := Validate(cnp); err != nil {
+			t.Errorf("Validate(%s) = %v, want nil", cnp, err)
+		}
+		if err := ValidateWith(cnp, ModernSIIEASC(2025), BirthYearRange(2024, 2025)); err != nil {
+			t.Errorf("ModernSIIEASC(2025) should decode %s to 2024–2025, got %v", cnp, err)
+		}
+	}
+
+	// Years past the reference year, or before 2024, keep their default century.
+	for _, cnp := range []string{
+		buildCNP("1", "25", "03", "01", "70", "001"), // 2025 is after the reference 2024
+		buildCNP("2", "23", "03", "01", "70", "001"),
+	} {
+		if err := ValidateWith(cnp, ModernSIIEASC(2024)); !errors.Is(err, ErrInvalidCounty) {
+			t.Errorf("ModernSIIEASC(2024) should reject %s, got %v", cnp, err)
+		}
+	}
+	if err := ValidateWith(buildCNP("1", "24", "03", "01", "12", "001"), ModernSIIEASC(2025), BirthYearRange(1900, 1999)); err != nil {
+		t.Errorf("Counties other than 70 should be unaffected, got %v", err)
+	}
+}
+
+func TestSIIEASCYear(t *testing.T) {
+	cases := []struct {
+		cnp     string
+		current int
+		want    int
+	}{
+		{buildCNP("8", "24", "01", "01", "70", "001"), 2025, 2024},
+		{buildCNP("9", "25", "01", "01", "70", "001"), 2025, 2025},
+		{buildCNP("9", "26", "01", "01", "70", "001"), 2025, 1926}, // not yet issued
+		{buildCNP("7", "99", "01", "01", "70", "001"), 2025, 1999},
+		{buildCNP("1", "24", "01", "01", "70", "001"), 2025, 1924},
+	}
+	for _, tc := range cases {
+		year, _, _ := cnpBirthDate(tc.cnp)
+		if got := siieascYear(fields(tc.cnp), year, tc.current, false); got != tc.want {
+			t.Errorf("siieascYear(%s, %d) = %d, want %d", tc.cnp, tc.current, got, tc.want)
+		}
+	}
+	if got := siieascYear(fields(cases[4].cnp), 1924, 2025, true); got != 2024 {
+		t.Errorf("siieascYear with anyS = %d, want 2024", got)
+	}
+}
+
+func TestValidateWith_Blocklist(t *testing.T) {
+	revoked := buildCNP("1", "80", "01", "01", "01", "001")
+	other := buildCNP("1", "80", "01", "01", "01", "002")
+	v := NewValidator(WithBlocklist(map[string]struct{}{revoked: {}, "123": {}}))
+
+	if err := v.Validate(revoked); !errors.Is(err, ErrBlocked) {
+		t.Errorf("Blocked CNP should fail with ErrBlocked, got %v", err)
+	}
+	if err := v.Validate(other); err != nil {
+		t.Errorf("CNP not in the blocklist should pass, got %v", err)
+	}
+	// Format errors win over the blocklist.
+	if err := v.Validate("123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Malformed input should fail its format check, got %v", err)
+	}
+	if err := Validate(revoked); err != nil {
+		t.Errorf("There should be no blocklist by default, got %v", err)
+	}
+}
+
+// BenchmarkValidateWith rebuilds the configuration on every call, while
+// BenchmarkValidator_Validate reuses one built by NewValidator.
+func BenchmarkValidateWith(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	blocked := map[string]struct{}{buildCNP("1", "80", "01", "01", "01", "001"): {}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ValidateWith(cnp, MinBirthYear(1900), WithBlocklist(blocked), SIIEASCMode())
+	}
+}
+
+func BenchmarkValidator_Validate(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	blocked := map[string]struct{}{buildCNP("1", "80", "01", "01", "01", "001"): {}}
+	v := NewValidator(MinBirthYear(1900), WithBlocklist(blocked), SIIEASCMode())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.Validate(cnp)
+	}
+}
@@ -0,0 +1,36 @@
+// ABOUTME: Compact integer keys for CNPs, for database indexing.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maxPackedID is the largest integer with at most 13 decimal digits.
+const maxPackedID = 9_999_999_999_999
+
+// PackedID validates the CNP and returns its 13 digits read as one base-10
+// integer, a compact numeric key that Unpack turns back into the CNP.
+// Returns an error if the CNP is invalid.
+func PackedID(cnp string) (uint64, error) {
+	if err := Validate(cnp); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(cnp, 10, 64)
+}
+
+// Unpack returns the CNP for an id produced by PackedID, zero-padded back to 13
+// digits so that leading zeros in later fields survive, and validates it.
+// Returns an error if id has more than 13 digits or the CNP is invalid.
+func Unpack(id uint64) (string, error) {
+	if id > maxPackedID {
+		return "", fmt.Errorf("%w: id %d has more than %d digits", ErrInvalidLength, id, cnpLength)
+	}
+	cnp := fmt.Sprintf("%0*d", cnpLength, id)
+	if err := Validate(cnp); err != nil {
+		return "", err
+	}
+	return cnp, nil
+}
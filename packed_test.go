@@ -0,0 +1,38 @@
+// ABOUTME: Tests for packing CNPs into integer keys and back.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPackedID(t *testing.T) {
+	for _, cnp := range []string{
+		buildCNP("1", "80", "01", "01", "01", "001"),
+		buildCNP("5", "00", "01", "01", "01", "001"), // zeros right after S
+		buildCNP("9", "99", "12", "31", "70", "999"),
+	} {
+		id, err := PackedID(cnp)
+		if err != nil {
+			t.Errorf("PackedID(%s) returned error: %v", cnp, err)
+			continue
+		}
+		if got, err := Unpack(id); err != nil || got != cnp {
+			t.Errorf("Unpack(%d) = %s, %v; want %s", id, got, err, cnp)
+		}
+	}
+
+	if id, _ := PackedID("5000101010011"); id != 5_000_101_010_011 {
+		t.Errorf("PackedID should read the digits as a base-10 integer, got %d", id)
+	}
+
+	if _, err := PackedID("123"); err == nil {
+		t.Errorf("PackedID should fail for an invalid CNP")
+	}
+	if _, err := Unpack(10_000_000_000_000); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Unpack should reject ids with more than 13 digits, got %v", err)
+	}
+	if _, err := Unpack(123); err == nil {
+		t.Errorf("Unpack should reject ids that do not decode to a valid CNP")
+	}
+}
@@ -0,0 +1,33 @@
+// ABOUTME: Parsing a CNP into its decoded, structured components.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "time"
+
+// CNP holds the decoded components of a valid CNP.
+type CNP struct {
+	S         byte      // S digit, '1'–'9'
+	Gender    Gender    // Male or Female; "" for S=9 (non-resident)
+	BirthDate time.Time // century-aware birth date at midnight UTC
+	County    string    // two-digit JJ county code
+	Serial    int       // NNN serial number, 1–999
+	Control   int       // control digit
+}
+
+// Parse validates the CNP and returns its decoded components.
+// Returns an error if the CNP is invalid.
+func Parse(cnp string) (*CNP, error) {
+	return ValidateAndParse(cnp)
+}
+
+// ValidateAndParse validates the CNP and, on success, returns the decoded
+// components from the same pass, so callers needing both avoid walking the
+// string twice. Returns nil and the validation error if the CNP is invalid.
+func ValidateAndParse(cnp string) (*CNP, error) {
+	var c CNP
+	if err := defaultConfig.check(cnp, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,69 @@
+// ABOUTME: Tests and benchmarks for parsing CNPs into structured components.
+package rossn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateAndParse(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	c, err := ValidateAndParse(cnp)
+	if err != nil {
+		t.Fatalf("ValidateAndParse(%s) returned error: %v", cnp, err)
+	}
+	want := CNP{
+		S:         '2',
+		Gender:    Female,
+		BirthDate: time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC),
+		County:    "40",
+		Serial:    123,
+		Control:   int(cnp[12] - '0'),
+	}
+	if *c != want {
+		t.Errorf("ValidateAndParse(%s) = %+v, want %+v", cnp, *c, want)
+	}
+
+	p, err := Parse(cnp)
+	if err != nil || *p != *c {
+		t.Errorf("Parse should agree with ValidateAndParse: %+v, %v", p, err)
+	}
+
+	// Century and S=9 handling.
+	c, _ = ValidateAndParse(buildCNP("3", "99", "12", "31", "39", "999"))
+	if c.BirthDate.Year() != 1899 || c.Gender != Male {
+		t.Errorf("S=3 should decode as a male born 1899, got %+v", c)
+	}
+	c, _ = ValidateAndParse(buildCNP("9", "90", "01", "01", "70", "555"))
+	if c.Gender != "" {
+		t.Errorf("S=9 should decode without a gender, got %q", c.Gender)
+	}
+}
+
+func TestValidateAndParse_Invalid(t *testing.T) {
+	for _, cnp := range []string{"123", "0000000000000", buildCNP("1", "80", "02", "30", "01", "001")} {
+		c, err := ValidateAndParse(cnp)
+		if err == nil || c != nil {
+			t.Errorf("ValidateAndParse(%s) should fail, got %+v, %v", cnp, c, err)
+		}
+		if (err == nil) != (Validate(cnp) == nil) {
+			t.Errorf("ValidateAndParse and Validate disagree on %s", cnp)
+		}
+	}
+}
+
+func BenchmarkValidateThenParse(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		if Validate(cnp) == nil {
+			_, _ = Parse(cnp)
+		}
+	}
+}
+
+func BenchmarkValidateAndParse(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		_, _ = ValidateAndParse(cnp)
+	}
+}
@@ -0,0 +1,91 @@
+// ABOUTME: RFC 7807 problem details describing why a CNP failed validation.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "errors"
+
+// FieldCNP names the CNP as a whole in problem details, for failures such as
+// a wrong length or a placeholder value that no single component explains.
+const FieldCNP = "cnp"
+
+// problemTypes maps each sentinel returned by Validate to the problem type
+// suffix, the component at fault and its byte offset. ErrNonDigit is absent,
+// as its field and offset depend on where the first non-digit is.
+var problemTypes = []struct {
+	err    error
+	slug   string
+	field  string
+	offset int
+}{
+	{ErrInvalidLength, "invalid-length", FieldCNP, 0},
+	{ErrPlaceholder, "placeholder", FieldCNP, 0},
+	{ErrInvalidGenderDigit, "invalid-gender-digit", FieldGender, offS},
+	{ErrInvalidDate, "invalid-date", FieldBirthDate, offYY},
+	{ErrInvalidCounty, "invalid-county", FieldCountyCode, offJJ},
+	{ErrInvalidSerial, "invalid-serial", FieldSerial, offNNN},
+	{ErrInvalidControlDigit, "invalid-control-digit", FieldControl, offC},
+}
+
+// ProblemDetail validates the CNP and, if it is invalid, returns an RFC 7807
+// problem details object ready for encoding/json, with the members "type" (a
+// "urn:rossn:problem:" URN such as "urn:rossn:problem:invalid-date"), "title",
+// "detail" (the error message), "field" (a Field* key naming the failing
+// component, or FieldCNP) and "offset" (the byte offset of that component, or
+// for ErrNonDigit of the offending byte, and for a wrong length the length
+// clamped to 13). For a valid CNP it returns nil and true.
+func ProblemDetail(cnp string) (detail map[string]any, ok bool) {
+	err := Validate(cnp)
+	if err == nil {
+		return nil, true
+	}
+	slug, field, offset := "invalid-cnp", FieldCNP, 0
+	if errors.Is(err, ErrNonDigit) {
+		offset = firstNonDigit(cnp)
+		slug, field = "non-digit", fieldAt(offset)
+	} else {
+		for _, p := range problemTypes {
+			if errors.Is(err, p.err) {
+				slug, field, offset = p.slug, p.field, p.offset
+				break
+			}
+		}
+		if errors.Is(err, ErrInvalidLength) {
+			offset = min(len(cnp), cnpLength)
+		}
+	}
+	return map[string]any{
+		"type":   "urn:rossn:problem:" + slug,
+		"title":  "Invalid CNP",
+		"detail": err.Error(),
+		"field":  field,
+		"offset": offset,
+	}, false
+}
+
+// firstNonDigit returns the index of the first byte of s that is not an ASCII
+// digit, or len(s) if there is none.
+func firstNonDigit(s string) int {
+	for i := 0; i < len(s); i++ {
+		if !isASCIIDigit(s[i]) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// fieldAt returns the Field* key of the component containing byte offset i.
+func fieldAt(i int) string {
+	switch {
+	case i < offYY:
+		return FieldGender
+	case i < offJJ:
+		return FieldBirthDate
+	case i < offNNN:
+		return FieldCountyCode
+	case i < offC:
+		return FieldSerial
+	default:
+		return FieldControl
+	}
+}
@@ -0,0 +1,49 @@
+// ABOUTME: Tests for RFC 7807 problem details.
+package rossn
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProblemDetail(t *testing.T) {
+	valid := buildCNP("1", "85", "03", "17", "40", "123")
+	if d, ok := ProblemDetail(valid); !ok || d != nil {
+		t.Errorf("ProblemDetail(%s) = %v, %v; want nil, true", valid, d, ok)
+	}
+
+	badControl := valid[:12] + string('0'+(valid[12]-'0'+1)%10)
+	cases := []struct {
+		cnp    string
+		typ    string
+		field  string
+		offset int
+	}{
+		{"123", "urn:rossn:problem:invalid-length", FieldCNP, 3},
+		{valid + "0", "urn:rossn:problem:invalid-length", FieldCNP, 13},
+		{"18503174x1231", "urn:rossn:problem:non-digit", FieldCountyCode, 8},
+		{"x850317401231", "urn:rossn:problem:non-digit", FieldGender, 0},
+		{"0000000000000", "urn:rossn:problem:placeholder", FieldCNP, 0},
+		{buildCNP("0", "85", "03", "17", "40", "123"), "urn:rossn:problem:invalid-gender-digit", FieldGender, 0},
+		{buildCNP("1", "85", "02", "30", "40", "123"), "urn:rossn:problem:invalid-date", FieldBirthDate, 1},
+		{buildCNP("1", "85", "03", "17", "49", "123"), "urn:rossn:problem:invalid-county", FieldCountyCode, 7},
+		{buildCNP("1", "85", "03", "17", "40", "000"), "urn:rossn:problem:invalid-serial", FieldSerial, 9},
+		{badControl, "urn:rossn:problem:invalid-control-digit", FieldControl, 12},
+	}
+	for _, tc := range cases {
+		d, ok := ProblemDetail(tc.cnp)
+		if ok || d["type"] != tc.typ || d["field"] != tc.field || d["offset"] != tc.offset {
+			t.Errorf("ProblemDetail(%q) = %v, %v; want type %s, field %s, offset %d", tc.cnp, d, ok, tc.typ, tc.field, tc.offset)
+			continue
+		}
+		if d["title"] != "Invalid CNP" || d["detail"] != Validate(tc.cnp).Error() {
+			t.Errorf("ProblemDetail(%q) title/detail = %v / %v", tc.cnp, d["title"], d["detail"])
+		}
+	}
+
+	d, _ := ProblemDetail("123")
+	b, err := json.Marshal(d)
+	if err != nil || string(b) != `{"detail":"CNP must be 13 digits","field":"cnp","offset":3,"title":"Invalid CNP","type":"urn:rossn:problem:invalid-length"}` {
+		t.Errorf("json.Marshal(ProblemDetail) = %s, %v", b, err)
+	}
+}
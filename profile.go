@@ -0,0 +1,69 @@
+// ABOUTME: Instrumented validation reporting which rule rejected a CNP.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "errors"
+
+// Stages reported by ValidateProfiled, numbered in the order Validate runs
+// them except for StagePlaceholder and StageGenderDigit, which were added later
+// and keep the numbering of the others stable. Placeholders are checked right
+// after the digits and the S digit right before the date. StageOther covers
+// every other rejection, such as policy options and WithExtraCheck errors.
+const (
+	StageValid       = -1
+	StageLength      = 0
+	StageDigits      = 1
+	StageDate        = 2
+	StageCounty      = 3
+	StageSerial      = 4
+	StageChecksum    = 5
+	StagePlaceholder = 6
+	StageGenderDigit = 7
+	StageOther       = 8
+)
+
+// stageErrors maps each stage to the sentinel Validate returns when it fails.
+var stageErrors = [...]struct {
+	stage int
+	err   error
+}{
+	{StageLength, ErrInvalidLength},
+	{StageDigits, ErrNonDigit},
+	{StagePlaceholder, ErrPlaceholder},
+	{StageGenderDigit, ErrInvalidGenderDigit},
+	{StageDate, ErrInvalidDate},
+	{StageCounty, ErrInvalidCounty},
+	{StageSerial, ErrInvalidSerial},
+	{StageChecksum, ErrInvalidControlDigit},
+}
+
+// ValidateProfiled validates the CNP exactly as Validate does and also returns
+// the Stage* constant of the rule that rejected it, or StageValid (-1) if it
+// passed. Tallying stages over a representative dataset shows which checks
+// reject most inputs.
+func ValidateProfiled(cnp string) (stage int, err error) {
+	err = Validate(cnp)
+	return stageOf(err), err
+}
+
+// ValidateProfiled is like the package-level ValidateProfiled under the
+// Validator's options.
+func (v *Validator) ValidateProfiled(cnp string) (stage int, err error) {
+	err = v.cfg.validate(cnp)
+	return stageOf(err), err
+}
+
+// stageOf returns the stage whose sentinel err wraps, StageValid for nil, and
+// StageOther for an error matching no built-in stage.
+func stageOf(err error) int {
+	if err == nil {
+		return StageValid
+	}
+	for _, s := range stageErrors {
+		if errors.Is(err, s.err) {
+			return s.stage
+		}
+	}
+	return StageOther
+}
@@ -0,0 +1,56 @@
+// ABOUTME: Tests for stage-reporting validation.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateProfiled(t *testing.T) {
+	valid := buildCNP("1", "85", "03", "17", "40", "123")
+	cases := []struct {
+		cnp   string
+		stage int
+	}{
+		{valid, StageValid},
+		{"123", StageLength},
+		{"18503174x1231", StageDigits},
+		{"0000000000000", StagePlaceholder},
+		{buildCNP("0", "85", "03", "17", "40", "123"), StageGenderDigit},
+		{buildCNP("1", "85", "02", "30", "40", "123"), StageDate},
+		{buildCNP("1", "85", "03", "17", "49", "123"), StageCounty},
+		{buildCNP("1", "85", "03", "17", "40", "000"), StageSerial},
+		{valid[:12] + string('0'+(valid[12]-'0'+1)%10), StageChecksum},
+	}
+	for _, tc := range cases {
+		stage, err := ValidateProfiled(tc.cnp)
+		if stage != tc.stage || err != Validate(tc.cnp) {
+			t.Errorf("ValidateProfiled(%q) = %d, %v; want %d, %v", tc.cnp, stage, err, tc.stage, Validate(tc.cnp))
+		}
+	}
+}
+
+func TestValidator_ValidateProfiled(t *testing.T) {
+	errReserved := errors.New("reserved serial")
+	v := NewValidator(WithExtraCheck(func(cnp string) error {
+		if cnp[9:12] == "777" {
+			return errReserved
+		}
+		return nil
+	}))
+	cases := []struct {
+		cnp   string
+		stage int
+		err   error
+	}{
+		{buildCNP("1", "85", "03", "17", "40", "123"), StageValid, nil},
+		{buildCNP("1", "85", "03", "17", "40", "777"), StageOther, errReserved},
+		{buildCNP("1", "85", "03", "17", "49", "777"), StageCounty, ErrInvalidCounty},
+	}
+	for _, tc := range cases {
+		stage, err := v.ValidateProfiled(tc.cnp)
+		if stage != tc.stage || !errors.Is(err, tc.err) {
+			t.Errorf("ValidateProfiled(%q) = %d, %v; want %d, %v", tc.cnp, stage, err, tc.stage, tc.err)
+		}
+	}
+}
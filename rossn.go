@@ -8,145 +8,335 @@ import (
 	"fmt"
 	"strconv"
 	"time"
-	"unicode"
 )
 
+// Errors returned by Validate, one per rule. Use errors.Is to test for them.
+var (
+	ErrInvalidLength       = errors.New("CNP must be 13 digits")
+	ErrNonDigit            = errors.New("CNP must contain only digits")
+	ErrInvalidGenderDigit  = errors.New("invalid gender digit in CNP")
+	ErrInvalidDate         = errors.New("invalid birth date in CNP")
+	ErrInvalidCounty       = errors.New("invalid county code in CNP")
+	ErrInvalidSerial       = errors.New("invalid serial number")
+	ErrInvalidControlDigit = errors.New("invalid control digit")
+)
+
+// Offsets of the components within a CNP laid out as SYYMMDDJJNNNC.
+// Every component is two digits long except S and C (one) and NNN (three).
+const (
+	offS      = 0
+	offYY     = 1
+	offMM     = 3
+	offDD     = 5
+	offJJ     = 7
+	offNNN    = 9
+	offC      = 12
+	cnpLength = 13
+)
+
+// cnpFields holds the raw components of a CNP as sliced by fields.
+type cnpFields struct {
+	s   byte   // S: gender, century and residency
+	yy  string // YY: two-digit birth year
+	mm  string // MM: birth month
+	dd  string // DD: birth day
+	jj  string // JJ: county code
+	nnn string // NNN: serial number
+	c   byte   // C: control digit
+}
+
+// fields slices a CNP of at least cnpLength bytes into its components.
+// It is the only place that knows the component offsets.
+func fields(cnp string) cnpFields {
+	return cnpFields{
+		s:   cnp[offS],
+		yy:  cnp[offYY:offMM],
+		mm:  cnp[offMM:offDD],
+		dd:  cnp[offDD:offJJ],
+		jj:  cnp[offJJ:offNNN],
+		nnn: cnp[offNNN:offC],
+		c:   cnp[offC],
+	}
+}
+
 // Validate checks if a CNP is valid according to all official Romanian rules.
-// It verifies length, digit content, date, county, serial, and checksum.
+// It verifies length, digit content, S digit, date, county, serial, and checksum.
+// Well-known placeholder values are rejected with ErrPlaceholder.
 // Returns nil if valid, or an error describing the failure.
 func Validate(cnp string) error {
-	if len(cnp) != 13 {
-		return errors.New("CNP must be 13 digits")
+	return defaultConfig.validate(cnp)
+}
+
+// QuickReject reports whether cnp is definitely invalid judging only by its
+// length, its digits and its control digit, the cheapest checks, as a first-pass
+// filter over large datasets. A false result does not mean the CNP is valid: the
+// date, county and other rules are skipped, so run Validate on the survivors.
+func QuickReject(cnp string) bool {
+	return !isStructural(cnp) || !hasValidControlDigit(cnp)
+}
+
+// isStructural reports whether s has the shape of a CNP: 13 ASCII digits.
+func isStructural(s string) bool {
+	if len(s) != cnpLength {
+		return false
 	}
-	for _, r := range cnp {
-		if !unicode.IsDigit(r) {
-			return errors.New("CNP must contain only digits")
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
 		}
 	}
+	return true
+}
+
+// validate runs the validation rules under the receiver's configuration.
+func (c *config) validate(cnp string) error {
+	return c.decode(cnp, nil)
+}
+
+// decode is like validate, also filling out as check does. Every Validator
+// method goes through it so that WithMetrics sees each validation.
+func (c *config) decode(cnp string, out *CNP) error {
+	err := c.check(cnp, out)
+	if c.metrics != nil {
+		c.metrics.record(err)
+	}
+	return err
+}
+
+// check is the single validation pass shared by Validate and Parse. When out is
+// non-nil and the CNP is valid, it is filled from the values decoded on the way.
+func (c *config) check(cnp string, out *CNP) error {
+	if len(cnp) != cnpLength {
+		return ErrInvalidLength
+	}
+	for i := 0; i < len(cnp); i++ {
+		if cnp[i] < '0' || cnp[i] > '9' {
+			return ErrNonDigit // including non-ASCII digits; see ValidateLenient
+		}
+	}
+	if _, ok := c.placeholders[cnp]; ok {
+		return ErrPlaceholder
+	}
+	f := fields(cnp)
+	if !isValidGenderDigit(f.s) {
+		return ErrInvalidGenderDigit
+	}
 	if !isValidDate(cnp) {
-		return errors.New("invalid birth date in CNP")
+		return ErrInvalidDate
+	}
+	year, month, day := cnpBirthDate(cnp)
+	if c.siieasc {
+		current := c.siieascRef
+		if current == 0 {
+			current = c.clock.Now().Year()
+		}
+		year = siieascYear(f, year, current, c.siieascAnyS)
 	}
-	if !isValidCounty(cnp) {
-		return errors.New("invalid county code in CNP")
+	birth := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	archivalWarning := false
+	if !countyAllowed(f.jj, f.s, birth) {
+		if c.warnArchival == nil || !isArchivalCounty(cnp) {
+			return ErrInvalidCounty
+		}
+		archivalWarning = true
 	}
-	if !isValidSerial(cnp) {
-		return errors.New("invalid serial number")
+	serial, err := strconv.Atoi(f.nnn)
+	if err != nil || serial < 1 || serial > 999 {
+		return ErrInvalidSerial
 	}
 	if !hasValidControlDigit(cnp) {
-		return errors.New("invalid control digit")
+		return ErrInvalidControlDigit
+	}
+	if _, ok := c.blocklist[cnp]; ok {
+		return ErrBlocked
+	}
+	if c.serialOK != nil && !c.serialOK(serial) {
+		return ErrSerialRejected
+	}
+	if !c.maxSerialWarn && c.serialTooHigh(serial) {
+		return ErrSerialTooHigh
+	}
+	if c.minBirthYear != 0 && year < c.minBirthYear {
+		return ErrBirthYearTooEarly
+	}
+	if r := c.yearRange; r != nil && (year < r[0] || year > r[1]) {
+		return ErrBirthYearOutOfRange
+	}
+	if _, ok := c.suspiciousDates[year*10000+month*100+day]; ok {
+		return ErrSuspiciousDate
+	}
+	for _, extra := range c.extraChecks {
+		if err := extra(cnp); err != nil {
+			return err
+		}
+	}
+	if archivalWarning {
+		c.warnArchival(cnp)
+	}
+	if out != nil {
+		*out = CNP{
+			S:         f.s,
+			Gender:    genderOf(f.s),
+			BirthDate: birth,
+			County:    f.jj,
+			Serial:    serial,
+			Control:   int(f.c - '0'),
+		}
 	}
 	return nil
 }
 
+// isValidGenderDigit checks that the S digit is one of the assigned values 1–9.
+func isValidGenderDigit(s byte) bool {
+	_, ok := sInfo(s)
+	return ok
+}
+
 // isValidDate checks if the CNP encodes a real, valid birth date
 // according to the S digit and YYMMDD fields.
 func isValidDate(cnp string) bool {
-	s := cnp[0]
-	yy := cnp[1:3]
-	mm := cnp[3:5]
-	dd := cnp[5:7]
-
-	century := "19"
-	switch s {
-	case '1', '2':
-		century = "19"
-	case '3', '4':
-		century = "18"
-	case '5', '6':
-		century = "20"
-	case '7', '8', '9':
-		century = "19"
-	default:
+	if _, ok := sInfo(fields(cnp).s); !ok {
 		return false
 	}
+	return isCalendarDate(cnpBirthDate(cnp))
+}
 
-	dateStr := fmt.Sprintf("%s%s-%s-%s", century, yy, mm, dd)
-	_, err := time.Parse("2006-01-02", dateStr)
-	return err == nil
+// isCalendarDate reports whether year-month-day is a real Gregorian date in a
+// year no earlier than 1. Month 00 or 13 and day 00 or past the month's end fail.
+func isCalendarDate(year, month, day int) bool {
+	return year >= 1 && month >= 1 && month <= 12 && day >= 1 && day <= daysInMonth(year, month)
 }
 
-// isValidCounty checks if the CNP encodes a valid Romanian county code (JJ).
+// monthDays holds the length of each month in a common year.
+var monthDays = [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// daysInMonth returns the number of days in month (1–12) of year.
+func daysInMonth(year, month int) int {
+	if month == 2 && isLeapYear(year) {
+		return 29
+	}
+	return monthDays[month-1]
+}
+
+// isLeapYear applies the Gregorian rule: years divisible by 4 are leap years,
+// except centuries, which are leap years only when divisible by 400 (so 1900
+// is not a leap year but 2000 is).
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// archivalCutoff is the first birth date for which the historic Bucharest
+// codes 47 and 48 are no longer valid.
+var archivalCutoff = time.Date(1979, 12, 19, 0, 0, 0, 0, time.UTC)
+
+// countyAllowed reports whether county is valid for a CNP with S digit s and the
+// given birth date (midnight UTC).
 // For JJ == "47" or "48" (historic Bucharest districts), validity is restricted
 // to dates before December 19, 1979. For JJ == "70", accepts any S for birth year
 // 2024 and later (SIIEASC CNPs), and only S=7,8,9 for prior years (legacy CNPs).
 // Other codes are validated according to the official list.
-func isValidCounty(cnp string) bool {
-	county := cnp[7:9]
-	s := cnp[0]
+func countyAllowed(county string, s byte, birth time.Time) bool {
 	switch county {
 	case "47", "48":
-		yyyy, mm, dd := cnpBirthDate(cnp)
-		boundary := time.Date(1979, 12, 19, 0, 0, 0, 0, time.UTC)
-		cnpDate := time.Date(yyyy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
-		return cnpDate.Before(boundary)
+		return birth.Before(archivalCutoff)
 	case "70":
-		yyyy, _, _ := cnpBirthDate(cnp)
-		if yyyy >= 2024 {
+		if isSIIEASC(birth) {
 			return true // After 2024: Accept for any S
 		}
 		// Before 2024: Only for S=7,8,9
-		return s == '7' || s == '8' || s == '9'
+		return s == SForeignMale || s == SForeignFemale || s == SNonResident
 	default:
-		valid := map[string]bool{
-			"01": true, "02": true, "03": true, "04": true, "05": true, "06": true,
-			"07": true, "08": true, "09": true, "10": true, "11": true, "12": true,
-			"13": true, "14": true, "15": true, "16": true, "17": true, "18": true,
-			"19": true, "20": true, "21": true, "22": true, "23": true, "24": true,
-			"25": true, "26": true, "27": true, "28": true, "29": true, "30": true,
-			"31": true, "32": true, "33": true, "34": true, "35": true, "36": true,
-			"37": true, "38": true, "39": true, "40": true, "41": true, "42": true,
-			"43": true, "44": true, "45": true, "46": true, "51": true, "52": true,
-		}
-		return valid[county]
+		return isStandardCounty(county)
 	}
 }
 
+// standardCounties marks, by numeric value, the county codes that are valid
+// regardless of S digit and birth date: 01–46, 51 and 52. It is built once so
+// that the lookup on the validation path neither allocates nor hashes.
+var standardCounties = func() (t [100]bool) {
+	for i := 1; i <= 46; i++ {
+		t[i] = true
+	}
+	t[51], t[52] = true, true
+	return t
+}()
+
+// isStandardCounty reports whether county is a two-digit code marked in standardCounties.
+func isStandardCounty(county string) bool {
+	if len(county) != 2 || county[0] < '0' || county[0] > '9' || county[1] < '0' || county[1] > '9' {
+		return false
+	}
+	return standardCounties[(county[0]-'0')*10+county[1]-'0']
+}
+
+// siieascStartYear is the first birth year of the SIIEASC era.
+const siieascStartYear = 2024
+
+// isSIIEASC reports whether a birth date falls in the SIIEASC era (2024 and
+// later), in which county code 70 is issued to any S digit.
+func isSIIEASC(birth time.Time) bool {
+	return birth.Year() >= siieascStartYear
+}
+
+// isArchivalCounty reports whether the CNP uses one of the historic
+// Bucharest district codes 47 or 48.
+func isArchivalCounty(cnp string) bool {
+	county := fields(cnp).jj
+	return county == "47" || county == "48"
+}
+
 // cnpBirthDate extracts the birth date (YYYY, MM, DD) from a CNP.
 // Returns (0,0,0) if the date cannot be determined (should not happen after isValidDate passes).
 func cnpBirthDate(cnp string) (year int, month int, day int) {
-	s := cnp[0]
-	yy := cnp[1:3]
-	mm := cnp[3:5]
-	dd := cnp[5:7]
-	var century string
-	switch s {
-	case '1', '2':
-		century = "19"
-	case '3', '4':
-		century = "18"
-	case '5', '6':
-		century = "20"
-	case '7', '8', '9':
-		century = "19"
-	default:
+	f := fields(cnp)
+	m, ok := sInfo(f.s)
+	if !ok {
 		return 0, 0, 0
 	}
-	y, _ := strconv.Atoi(century + yy)
-	m, _ := strconv.Atoi(mm)
-	d, _ := strconv.Atoi(dd)
-	return y, m, d
-}
-
-// isValidSerial checks if the NNN serial part of the CNP is in the official range 001–999.
-func isValidSerial(cnp string) bool {
-	serial := cnp[9:12]
-	val, err := strconv.Atoi(serial)
-	return err == nil && val >= 1 && val <= 999
+	y, _ := strconv.Atoi(f.yy)
+	mo, _ := strconv.Atoi(f.mm)
+	d, _ := strconv.Atoi(f.dd)
+	return m.century + y, mo, d
 }
 
 // hasValidControlDigit checks the CNP control digit using the official weighting scheme.
 func hasValidControlDigit(cnp string) bool {
-	const weights = "279146358279"
+	return controlDigit(cnp[:offC]) == int(fields(cnp).c-'0')
+}
+
+// controlWeights are the official per-position weights of the CNP checksum.
+var controlWeights = [12]int{2, 7, 9, 1, 4, 6, 3, 5, 8, 2, 7, 9}
+
+// ControlWeights returns the official weights applied to the first 12 digits
+// of a CNP: [2 7 9 1 4 6 3 5 8 2 7 9]. The control digit is the weighted sum of
+// those digits modulo 11, except that a remainder of 10 gives a control digit of 1.
+// The returned slice is a copy; modifying it does not affect validation.
+func ControlWeights() []int {
+	w := controlWeights
+	return w[:]
+}
+
+// WeightAt returns the official checksum weight of the digit at 0-based
+// position, from 2 at position 0 to 9 at position 11, without copying the whole
+// table as ControlWeights does. The control digit itself, at position 12, has
+// no weight, so positions outside 0–11 return an error.
+func WeightAt(position int) (int, error) {
+	if position < 0 || position >= len(controlWeights) {
+		return 0, fmt.Errorf("weight position %d out of range 0–%d", position, len(controlWeights)-1)
+	}
+	return controlWeights[position], nil
+}
+
+// controlDigit computes the control digit for the first 12 digits of a CNP.
+// A weighted sum modulo 11 of 10 yields a control digit of 1.
+func controlDigit(first12 string) int {
 	sum := 0
-	for i := 0; i < 12; i++ {
-		d, _ := strconv.Atoi(string(cnp[i]))
-		w, _ := strconv.Atoi(string(weights[i]))
-		sum += d * w
+	for i, w := range controlWeights {
+		sum += int(first12[i]-'0') * w
 	}
 	control := sum % 11
 	if control == 10 {
 		control = 1
 	}
-	last, _ := strconv.Atoi(string(cnp[12]))
-	return control == last
+	return control
 }
@@ -2,8 +2,11 @@
 package rossn
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func buildCNP(s, year, month, day, county, serial string) string {
@@ -117,6 +120,126 @@ func TestValidate_LeapYears(t *testing.T) {
 	}
 }
 
+// Date edge cases, including the century leap rule: 1800 and 1900 are not leap
+// years (divisible by 100), 2000 is (divisible by 400).
+func TestValidate_DateMatrix(t *testing.T) {
+	cases := []struct {
+		s, yy, mm, dd string
+		valid         bool
+	}{
+		{"1", "85", "00", "15", false},
+		{"1", "85", "13", "15", false},
+		{"1", "85", "06", "00", false},
+		{"1", "85", "01", "32", false},
+		{"1", "85", "01", "31", true},
+		{"1", "85", "04", "31", false},
+		{"1", "85", "12", "31", true},
+		{"3", "50", "00", "01", false},
+		{"5", "10", "13", "01", false},
+		{"6", "10", "11", "32", false},
+		{"3", "00", "02", "29", false}, // 1800
+		{"3", "96", "02", "29", true},  // 1896
+		{"1", "00", "02", "29", false}, // 1900
+		{"1", "00", "02", "28", true},  // 1900
+		{"9", "00", "02", "29", false}, // 1900, non-resident
+		{"5", "00", "02", "29", true},  // 2000
+		{"6", "00", "02", "30", false}, // 2000
+		{"5", "04", "02", "29", true},  // 2004
+		{"5", "23", "02", "29", false}, // 2023
+	}
+	for _, tc := range cases {
+		cnp := buildCNP(tc.s, tc.yy, tc.mm, tc.dd, "01", "123")
+		err := Validate(cnp)
+		if tc.valid && err != nil {
+			t.Errorf("CNP %s should be valid, got %v", cnp, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("CNP %s should fail with ErrInvalidDate, got %v", cnp, err)
+		}
+	}
+}
+
+// A month or day of 00 must fail with ErrInvalidDate for every S digit and
+// alongside every valid day or month, whichever entry point and options are
+// used, so a change to the manual date parsing cannot let a zero through. The
+// control digit is always correct, so only the date is at fault.
+func TestValidate_ZeroMonthOrDay(t *testing.T) {
+	var cnps []string
+	for s := '1'; s <= '9'; s++ {
+		for v := 0; v <= 31; v++ {
+			two := fmt.Sprintf("%02d", v)
+			if v <= 12 {
+				cnps = append(cnps, buildCNP(string(s), "85", two, "00", "12", "123"))
+			}
+			cnps = append(cnps, buildCNP(string(s), "85", "00", two, "12", "123"))
+		}
+		// SIIEASC county 70 and archival 47 exercise the other county paths.
+		cnps = append(cnps, buildCNP(string(s), "24", "00", "15", "70", "001"))
+		cnps = append(cnps, buildCNP(string(s), "75", "06", "00", "47", "001"))
+	}
+	validator := NewValidator(SIIEASCMode(), WarnArchival(func(string) {}))
+	for _, cnp := range cnps {
+		if err := Validate(cnp); !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("Validate(%s) = %v, want ErrInvalidDate", cnp, err)
+		}
+		if c, err := Parse(cnp); c != nil || !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("Parse(%s) = %+v, %v; want ErrInvalidDate", cnp, c, err)
+		}
+		if err := validator.Validate(cnp); !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("Validator.Validate(%s) = %v, want ErrInvalidDate", cnp, err)
+		}
+		if _, err := ValidateLenient(toScript(cnp, '０')); !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("ValidateLenient(full-width %s) = %v, want ErrInvalidDate", cnp, err)
+		}
+	}
+}
+
+// For every month, the last day must be accepted and the next rejected, in both
+// a common year (1981) and a leap year (1984).
+func TestValidate_MonthLengths(t *testing.T) {
+	lengths := []struct {
+		yy   string
+		days [12]int
+	}{
+		{"81", [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}},
+		{"84", [12]int{31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}},
+	}
+	for _, l := range lengths {
+		for i, last := range l.days {
+			mm := fmt.Sprintf("%02d", i+1)
+			ok := buildCNP("1", l.yy, mm, fmt.Sprintf("%02d", last), "01", "123")
+			if err := Validate(ok); err != nil {
+				t.Errorf("Last day 19%s-%s-%02d should be valid: %s, got %v", l.yy, mm, last, ok, err)
+			}
+			over := buildCNP("1", l.yy, mm, fmt.Sprintf("%02d", last+1), "01", "123")
+			if err := Validate(over); !errors.Is(err, ErrInvalidDate) {
+				t.Errorf("Day 19%s-%s-%02d should be invalid: %s, got %v", l.yy, mm, last+1, over, err)
+			}
+		}
+	}
+}
+
+// isCalendarDate must agree with time.Date normalisation for every day of the
+// encodable range and reject years before 1, such as 0000.
+func TestIsCalendarDate(t *testing.T) {
+	for year := 1800; year <= 2099; year++ {
+		for month := 0; month <= 13; month++ {
+			for day := 0; day <= 32; day++ {
+				d := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+				want := d.Year() == year && int(d.Month()) == month && d.Day() == day
+				if got := isCalendarDate(year, month, day); got != want {
+					t.Fatalf("isCalendarDate(%d, %d, %d) = %v, want %v", year, month, day, got, want)
+				}
+			}
+		}
+	}
+	for _, year := range []int{0, -1, -400} {
+		if isCalendarDate(year, 1, 1) {
+			t.Errorf("Year %d should not be accepted", year)
+		}
+	}
+}
+
 // Valid county codes (official)
 func TestValidate_AllValidCounties(t *testing.T) {
 	validCounties := []string{
@@ -299,3 +422,154 @@ func TestValidate_JJ70_PrePost2024(t *testing.T) {
 		}
 	}
 }
+
+func TestControlWeights(t *testing.T) {
+	want := []int{2, 7, 9, 1, 4, 6, 3, 5, 8, 2, 7, 9}
+	got := ControlWeights()
+	if len(got) != len(want) {
+		t.Fatalf("ControlWeights() has %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ControlWeights()[%d] = %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	// Mutating the returned slice must not affect later calls or validation.
+	got[0] = 0
+	if ControlWeights()[0] != 2 {
+		t.Errorf("ControlWeights() must return a copy")
+	}
+	cnp := buildCNP("1", "80", "01", "01", "01", "001")
+	if err := Validate(cnp); err != nil {
+		t.Errorf("Validation changed after mutating weights: %s, err=%v", cnp, err)
+	}
+}
+
+func TestWeightAt(t *testing.T) {
+	want := []int{2, 7, 9, 1, 4, 6, 3, 5, 8, 2, 7, 9}
+	for pos, w := range want {
+		if got, err := WeightAt(pos); err != nil || got != w {
+			t.Errorf("WeightAt(%d) = %d, %v; want %d", pos, got, err, w)
+		}
+	}
+	for _, pos := range []int{-1, 12, 13} {
+		if _, err := WeightAt(pos); err == nil {
+			t.Errorf("WeightAt(%d) should fail", pos)
+		}
+	}
+}
+
+func TestValidate_SentinelErrors(t *testing.T) {
+	valid := buildCNP("1", "80", "01", "01", "01", "001")
+	cases := []struct {
+		cnp  string
+		want error
+	}{
+		{"19801010012", ErrInvalidLength},
+		{"19X0101000123", ErrNonDigit},
+		{buildCNP("0", "80", "01", "01", "01", "001"), ErrInvalidGenderDigit},
+		{buildCNP("1", "80", "02", "30", "01", "001"), ErrInvalidDate},
+		{buildCNP("1", "80", "01", "01", "53", "001"), ErrInvalidCounty},
+		{buildCNP("1", "80", "01", "01", "01", "000"), ErrInvalidSerial},
+		{valid[:12] + "9", ErrInvalidControlDigit},
+	}
+	for _, tc := range cases {
+		if err := Validate(tc.cnp); !errors.Is(err, tc.want) {
+			t.Errorf("Validate(%s) = %v, want %v", tc.cnp, err, tc.want)
+		}
+	}
+}
+
+// S=0 must be reported as a gender-digit error, not as an invalid date.
+func TestValidate_GenderDigitZero(t *testing.T) {
+	for _, cnp := range []string{
+		"0980101000123",
+		buildCNP("0", "80", "01", "01", "01", "001"),
+		buildCNP("0", "90", "06", "15", "12", "123"),
+	} {
+		err := Validate(cnp)
+		if !errors.Is(err, ErrInvalidGenderDigit) {
+			t.Errorf("CNP with S=0 should fail with ErrInvalidGenderDigit: %s, got %v", cnp, err)
+		}
+		if errors.Is(err, ErrInvalidDate) {
+			t.Errorf("CNP with S=0 should not report a date error: %s", cnp)
+		}
+	}
+}
+
+// Each offset must map to the right component of SYYMMDDJJNNNC.
+func TestFields_Offsets(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	f := fields(cnp)
+	checks := []struct {
+		name, got, want string
+	}{
+		{"S", string(f.s), "2"},
+		{"YY", f.yy, "85"},
+		{"MM", f.mm, "03"},
+		{"DD", f.dd, "17"},
+		{"JJ", f.jj, "40"},
+		{"NNN", f.nnn, "123"},
+		{"C", string(f.c), cnp[12:]},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("Component %s of %s = %q, want %q", c.name, cnp, c.got, c.want)
+		}
+	}
+	if offC+1 != cnpLength {
+		t.Errorf("Control digit offset %d is not the last position of a %d-digit CNP", offC, cnpLength)
+	}
+	if all := string(f.s) + f.yy + f.mm + f.dd + f.jj + f.nnn + string(f.c); all != cnp {
+		t.Errorf("Components do not reassemble to the CNP: %s != %s", all, cnp)
+	}
+}
+
+func TestQuickReject(t *testing.T) {
+	valid := buildCNP("1", "80", "01", "01", "01", "001")
+	for _, cnp := range []string{"", "123", valid + "0", "12345678901ab", valid[:12] + string('0'+(valid[12]-'0'+1)%10)} {
+		if !QuickReject(cnp) {
+			t.Errorf("QuickReject(%q) should be true", cnp)
+		}
+	}
+	// Survivors need not be valid: Feb 30 has a correct control digit here.
+	for _, cnp := range []string{valid, buildCNP("1", "80", "02", "30", "01", "001")} {
+		if QuickReject(cnp) {
+			t.Errorf("QuickReject(%s) should be false", cnp)
+		}
+	}
+	// No CNP accepted by Validate is ever rejected.
+	for i := 0; i < 1000; i++ {
+		if cnp := Generate(); QuickReject(cnp) {
+			t.Fatalf("QuickReject rejected valid CNP %s", cnp)
+		}
+	}
+}
+
+// The precomputed county table must accept exactly 01–46, 51 and 52.
+func TestIsStandardCounty(t *testing.T) {
+	for i := 0; i <= 99; i++ {
+		code := fmt.Sprintf("%02d", i)
+		want := (i >= 1 && i <= 46) || i == 51 || i == 52
+		if got := isStandardCounty(code); got != want {
+			t.Errorf("isStandardCounty(%s) = %v, want %v", code, got, want)
+		}
+	}
+	for _, code := range []string{"", "1", "001", "1a", "a1", "\x00\x01"} {
+		if isStandardCounty(code) {
+			t.Errorf("isStandardCounty(%q) should be false", code)
+		}
+	}
+}
+
+// BenchmarkCountyAllowed measures the county lookup on the validation hot path,
+// cycling through standard, special and invalid codes.
+func BenchmarkCountyAllowed(b *testing.B) {
+	codes := []string{"01", "12", "40", "46", "47", "51", "52", "70", "49", "99"}
+	birth := time.Date(1985, 3, 17, 0, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		countyAllowed(codes[i%len(codes)], '1', birth)
+	}
+}
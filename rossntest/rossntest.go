@@ -0,0 +1,64 @@
+// ABOUTME: Test helpers for packages that validate Romanian CNP numbers with rossn.
+// MIT License – see LICENSE file.
+
+// Package rossntest provides assertions for tests in packages that use rossn.
+package rossntest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ac999/rossn"
+)
+
+// AssertValid marks the test as failed, naming the CNP and the validation
+// error, if cnp does not pass rossn.Validate. The test continues running.
+func AssertValid(t testing.TB, cnp string) {
+	t.Helper()
+	if err := rossn.Validate(cnp); err != nil {
+		t.Errorf("expected valid CNP %q, got error: %v", cnp, err)
+	}
+}
+
+// AssertInvalid marks the test as failed, naming the CNP, if cnp passes
+// rossn.Validate. The test continues running.
+func AssertInvalid(t testing.TB, cnp string) {
+	t.Helper()
+	if err := rossn.Validate(cnp); err == nil {
+		t.Errorf("expected invalid CNP %q, but it passed validation", cnp)
+	}
+}
+
+// FakeClock is a rossn.Clock whose time only changes when set or advanced, for
+// passing to rossn.WithClock. It is safe for concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock frozen at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
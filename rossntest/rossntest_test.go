@@ -0,0 +1,81 @@
+// ABOUTME: Tests for the rossntest assertion helpers.
+package rossntest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ac999/rossn"
+)
+
+// recorder captures failures instead of reporting them; other testing.TB
+// methods are not used by the helpers.
+type recorder struct {
+	testing.TB
+	helper bool
+	errors []string
+}
+
+func (r *recorder) Helper() { r.helper = true }
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertValid(t *testing.T) {
+	r := &recorder{}
+	AssertValid(r, rossn.Generate())
+	if len(r.errors) != 0 || !r.helper {
+		t.Errorf("AssertValid on a valid CNP: errors=%v, helper=%v", r.errors, r.helper)
+	}
+
+	r = &recorder{}
+	AssertValid(r, "123")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `"123"`) || !strings.Contains(r.errors[0], rossn.ErrInvalidLength.Error()) {
+		t.Errorf("AssertValid should report the CNP and error, got %v", r.errors)
+	}
+}
+
+func TestAssertInvalid(t *testing.T) {
+	r := &recorder{}
+	AssertInvalid(r, "123")
+	if len(r.errors) != 0 || !r.helper {
+		t.Errorf("AssertInvalid on an invalid CNP: errors=%v, helper=%v", r.errors, r.helper)
+	}
+
+	cnp := rossn.Generate()
+	r = &recorder{}
+	AssertInvalid(r, cnp)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], cnp) {
+		t.Errorf("AssertInvalid should report the CNP, got %v", r.errors)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := NewFakeClock(start)
+	if !clk.Now().Equal(start) {
+		t.Errorf("Now() = %v, want %v", clk.Now(), start)
+	}
+	clk.Advance(36 * time.Hour)
+	if want := start.Add(36 * time.Hour); !clk.Now().Equal(want) {
+		t.Errorf("After Advance, Now() = %v, want %v", clk.Now(), want)
+	}
+
+	// The clock decides which county-70 S=8 CNPs SIIEASCMode decodes as 20YY.
+	cnp, err := rossn.FromComponents(8, 1925, 5, 10, 70, 123)
+	if err != nil {
+		t.Fatalf("FromComponents failed: %v", err)
+	}
+	opts := []rossn.Option{rossn.WithClock(clk), rossn.SIIEASCMode(), rossn.MinBirthYear(2000)}
+	clk.Set(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err := rossn.ValidateWith(cnp, opts...); err == nil {
+		t.Errorf("In 2024, %s should still decode to 1925", cnp)
+	}
+	clk.Set(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := rossn.ValidateWith(cnp, opts...); err != nil {
+		t.Errorf("In 2025, %s should decode to 2025, got %v", cnp, err)
+	}
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Finding and masking valid CNPs embedded in free text and log streams.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// FindCNPs returns, in order of appearance, every valid CNP embedded in text as
+// a run of exactly 13 ASCII digits. Longer digit runs, such as card or phone
+// numbers, are not searched for CNPs inside them.
+func FindCNPs(text string) []string {
+	var found []string
+	for _, span := range cnpSpans(text) {
+		found = append(found, text[span[0]:span[1]])
+	}
+	return found
+}
+
+// cnpSpans returns the [start, end) byte offsets of the valid CNPs in text.
+func cnpSpans(text string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(text); {
+		if !isASCIIDigit(text[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && isASCIIDigit(text[i]) {
+			i++
+		}
+		if i-start == cnpLength && Validate(text[start:i]) == nil {
+			spans = append(spans, [2]int{start, i})
+		}
+	}
+	return spans
+}
+
+func isASCIIDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// MaskStream copies r to w line by line, replacing each valid CNP found by
+// FindCNPs with its masked form (the S digit followed by twelve '*'). Only one
+// line is held in memory at a time, and since a CNP cannot span a newline none
+// is missed at buffer boundaries. Returns the first read or write error.
+func MaskStream(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadString('\n')
+		if line != "" {
+			if _, err := io.WriteString(w, maskLine(line)); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// maskLine returns line with every valid CNP in it masked.
+func maskLine(line string) string {
+	spans := cnpSpans(line)
+	if len(spans) == 0 {
+		return line
+	}
+	var b strings.Builder
+	b.Grow(len(line))
+	last := 0
+	for _, span := range spans {
+		b.WriteString(line[last:span[0]])
+		b.WriteString(mask(line[span[0]:span[1]]))
+		last = span[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
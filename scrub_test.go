@@ -0,0 +1,52 @@
+// ABOUTME: Tests for finding and masking CNPs in free text.
+package rossn
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFindCNPs(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	text := "user=" + a + ", alt:" + b + " card 4" + a + " bad 1234567890123 end" + b
+	got := FindCNPs(text)
+	if strings.Join(got, ",") != a+","+b+","+b {
+		t.Errorf("FindCNPs = %v, want [%s %s %s]", got, a, b, b)
+	}
+	if got := FindCNPs("no numbers here"); got != nil {
+		t.Errorf("FindCNPs without CNPs = %v, want nil", got)
+	}
+}
+
+func TestMaskStream(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	b := buildCNP("2", "90", "01", "01", "12", "001")
+	input := "login " + a + " ok\r\n" +
+		"no cnp here\n" +
+		a + b + "\n" + // 26 digits: not a CNP
+		"pair " + a + "," + b + "\n" +
+		"tail " + b // no trailing newline
+	want := "login 1************ ok\r\n" +
+		"no cnp here\n" +
+		a + b + "\n" +
+		"pair 1************,2************\n" +
+		"tail 2************"
+
+	// One byte per read forces every CNP across read boundaries.
+	var out strings.Builder
+	if err := MaskStream(iotest.OneByteReader(strings.NewReader(input)), &out); err != nil {
+		t.Fatalf("MaskStream returned error: %v", err)
+	}
+	if out.String() != want {
+		t.Errorf("MaskStream output = %q, want %q", out.String(), want)
+	}
+
+	if err := MaskStream(strings.NewReader(input), failingWriter{}); err == nil {
+		t.Errorf("MaskStream should report write errors")
+	}
+	if err := MaskStream(iotest.ErrReader(iotest.ErrTimeout), &out); err != iotest.ErrTimeout {
+		t.Errorf("MaskStream should report read errors, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// ABOUTME: The meaning of each S digit, the single source for century, gender and residency.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import "fmt"
+
+// S digit values. The S digit encodes gender and either the birth century or
+// the holder's residency status.
+const (
+	SMale1900      byte = '1' // male, born 1900–1999
+	SFemale1900    byte = '2' // female, born 1900–1999
+	SMale1800      byte = '3' // male, born 1800–1899
+	SFemale1800    byte = '4' // female, born 1800–1899
+	SMale2000      byte = '5' // male, born 2000–2099
+	SFemale2000    byte = '6' // female, born 2000–2099
+	SForeignMale   byte = '7' // male foreign resident (decoded as born 1900–1999)
+	SForeignFemale byte = '8' // female foreign resident (decoded as born 1900–1999)
+	SNonResident   byte = '9' // non-resident, no gender (decoded as born 1900–1999)
+)
+
+// sMeaning describes one S digit.
+type sMeaning struct {
+	century     int             // first year of the century used to decode YY
+	gender      Gender          // "" when no gender is encoded
+	residency   ResidencyStatus // as reported by Fields and ValidateAndClassify
+	description string
+}
+
+// sTable maps each S digit, indexed by its value, to its meaning. Index 0 is the
+// zero value, marking the digit as unassigned. Every helper that interprets the
+// S digit reads this table.
+var sTable = [10]sMeaning{
+	1: {1900, Male, ResidencyCitizen, "male, born 1900–1999"},
+	2: {1900, Female, ResidencyCitizen, "female, born 1900–1999"},
+	3: {1800, Male, ResidencyCitizen, "male, born 1800–1899"},
+	4: {1800, Female, ResidencyCitizen, "female, born 1800–1899"},
+	5: {2000, Male, ResidencyCitizen, "male, born 2000–2099"},
+	6: {2000, Female, ResidencyCitizen, "female, born 2000–2099"},
+	7: {1900, Male, ResidencyForeign, "male foreign resident"},
+	8: {1900, Female, ResidencyForeign, "female foreign resident"},
+	9: {1900, "", ResidencyNonResident, "non-resident"},
+}
+
+// sInfo returns the meaning of the S digit and whether it is assigned.
+func sInfo(s byte) (sMeaning, bool) {
+	if s < '0' || s > '9' {
+		return sMeaning{}, false
+	}
+	m := sTable[s-'0']
+	return m, m.century != 0
+}
+
+// DescribeS returns a description of the S digit, such as "male, born 1900–1999"
+// or "non-resident". Returns ErrInvalidGenderDigit for digits other than 1–9.
+func DescribeS(s byte) (string, error) {
+	m, ok := sInfo(s)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidGenderDigit, s)
+	}
+	return m.description, nil
+}
+
+// CenturyConfidence returns the first year of the century the S digit assigns
+// to the birth date, such as 1900, and whether that inference is ambiguous. It
+// is for S=7, 8 and 9, which identify residency rather than a century and are
+// decoded as 1900–1999 by convention; callers may ask for clarification or apply
+// a reference-year heuristic such as SIIEASCMode. Only the structure is checked:
+// it returns ErrInvalidLength or ErrNonDigit unless cnp is 13 ASCII digits, and
+// ErrInvalidGenderDigit for S=0. Date, county and checksum are not validated.
+func CenturyConfidence(cnp string) (century int, ambiguous bool, err error) {
+	if len(cnp) != cnpLength {
+		return 0, false, ErrInvalidLength
+	}
+	if !isStructural(cnp) {
+		return 0, false, ErrNonDigit
+	}
+	s := cnp[offS]
+	m, ok := sInfo(s)
+	if !ok {
+		return 0, false, ErrInvalidGenderDigit
+	}
+	return m.century, s >= SForeignMale, nil
+}
@@ -0,0 +1,103 @@
+// ABOUTME: Tests for the S digit table and the helpers derived from it.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDescribeS(t *testing.T) {
+	cases := map[byte]string{
+		SMale1900:      "male, born 1900–1999",
+		SFemale1900:    "female, born 1900–1999",
+		SMale1800:      "male, born 1800–1899",
+		SFemale1800:    "female, born 1800–1899",
+		SMale2000:      "male, born 2000–2099",
+		SFemale2000:    "female, born 2000–2099",
+		SForeignMale:   "male foreign resident",
+		SForeignFemale: "female foreign resident",
+		SNonResident:   "non-resident",
+	}
+	for s, want := range cases {
+		if got, err := DescribeS(s); err != nil || got != want {
+			t.Errorf("DescribeS(%c) = %q, %v; want %q", s, got, err, want)
+		}
+	}
+	for _, s := range []byte{'0', 'a', 0, 255} {
+		if _, err := DescribeS(s); !errors.Is(err, ErrInvalidGenderDigit) {
+			t.Errorf("DescribeS(%q) should fail with ErrInvalidGenderDigit, got %v", s, err)
+		}
+	}
+}
+
+// The decoded century, gender and residency must agree with the table for every S.
+func TestSTable_Consistency(t *testing.T) {
+	want := []struct {
+		s         byte
+		year      int
+		gender    Gender
+		residency string
+	}{
+		{'1', 1990, Male, "citizen"},
+		{'2', 1990, Female, "citizen"},
+		{'3', 1890, Male, "citizen"},
+		{'4', 1890, Female, "citizen"},
+		{'5', 2090, Male, "citizen"},
+		{'6', 2090, Female, "citizen"},
+		{'7', 1990, Male, "foreign_resident"},
+		{'8', 1990, Female, "foreign_resident"},
+		{'9', 1990, "", "non_resident"},
+	}
+	for _, w := range want {
+		cnp := buildCNP(string(w.s), "90", "10", "10", "10", "101")
+		if y, _, _ := cnpBirthDate(cnp); y != w.year {
+			t.Errorf("S=%c decodes year %d, want %d", w.s, y, w.year)
+		}
+		if g := genderOf(w.s); g != w.gender {
+			t.Errorf("S=%c decodes gender %q, want %q", w.s, g, w.gender)
+		}
+		if r := residencyCode(w.s); r != w.residency {
+			t.Errorf("S=%c decodes residency %q, want %q", w.s, r, w.residency)
+		}
+		if w.s != SNonResident && w.s != SForeignMale && w.s != SForeignFemale {
+			if got := sDigitFor(w.gender, w.year, false); got != w.s {
+				t.Errorf("sDigitFor(%s, %d) = %c, want %c", w.gender, w.year, got, w.s)
+			}
+		}
+	}
+}
+
+func TestCenturyConfidence(t *testing.T) {
+	cases := []struct {
+		s         string
+		century   int
+		ambiguous bool
+	}{
+		{"1", 1900, false}, {"2", 1900, false},
+		{"3", 1800, false}, {"4", 1800, false},
+		{"5", 2000, false}, {"6", 2000, false},
+		{"7", 1900, true}, {"8", 1900, true}, {"9", 1900, true},
+	}
+	for _, tc := range cases {
+		// The checksum is deliberately wrong: only the structure is checked.
+		cnp := tc.s + "850230401230"
+		century, ambiguous, err := CenturyConfidence(cnp)
+		if err != nil || century != tc.century || ambiguous != tc.ambiguous {
+			t.Errorf("CenturyConfidence(%s) = %d, %v, %v; want %d, %v", cnp, century, ambiguous, err, tc.century, tc.ambiguous)
+		}
+	}
+
+	errs := []struct {
+		cnp  string
+		want error
+	}{
+		{"123", ErrInvalidLength},
+		{"18503174012x3", ErrNonDigit},
+		{"0850317401231", ErrInvalidGenderDigit},
+	}
+	for _, tc := range errs {
+		if _, _, err := CenturyConfidence(tc.cnp); !errors.Is(err, tc.want) {
+			t.Errorf("CenturyConfidence(%s) error = %v, want %v", tc.cnp, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// ABOUTME: Streaming validation of newline-delimited CNP input.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ReportErrorLimit is the maximum number of failures recorded in Report.Errors.
+// Failures beyond the limit are still counted in Report.Invalid.
+const ReportErrorLimit = 100
+
+// ErrRecordTooShort is reported by ValidateFixedWidth for a line that ends
+// before the CNP's column range does, and by AnalyzeCSV for a record
+// with too few fields to reach the CNP column.
+var ErrRecordTooShort = errors.New("record too short for the CNP column range")
+
+// Report summarises the validation of a stream of CNPs.
+type Report struct {
+	Total   int         `json:"total"`
+	Valid   int         `json:"valid"`
+	Invalid int         `json:"invalid"`
+	Errors  []LineError `json:"errors"`
+}
+
+// LineError describes a CNP that failed validation on a given input line.
+type LineError struct {
+	Line int    // 1-based line number in the input
+	CNP  string // the trimmed line content
+	Err  error  // the validation error
+}
+
+// Error implements the error interface.
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as its message so reports can be returned as JSON.
+func (e LineError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Line  int    `json:"line"`
+		CNP   string `json:"cnp"`
+		Error string `json:"error"`
+	}{e.Line, e.CNP, e.Err.Error()})
+}
+
+// ValidateReport validates one CNP per line from r and returns the totals.
+// Surrounding whitespace is trimmed and blank lines are skipped without being counted.
+// Only the first ReportErrorLimit failures are kept; lines are not retained otherwise.
+// The returned error is non-nil only if reading r fails.
+func ValidateReport(r io.Reader) (Report, error) {
+	rep := Report{Errors: []LineError{}}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		cnp := strings.TrimSpace(sc.Text())
+		if cnp == "" {
+			continue
+		}
+		rep.Total++
+		if err := Validate(cnp); err != nil {
+			rep.Invalid++
+			if len(rep.Errors) < ReportErrorLimit {
+				rep.Errors = append(rep.Errors, LineError{Line: line, CNP: cnp, Err: err})
+			}
+			continue
+		}
+		rep.Valid++
+	}
+	return rep, sc.Err()
+}
+
+// Partition reads newline-delimited input from r and copies each line, verbatim
+// and including its line ending, to validW if its trimmed content passes Validate
+// and to invalidW otherwise (blank lines count as invalid). It streams one line
+// at a time and returns the number of lines written to each writer, stopping at
+// the first read or write error.
+func Partition(r io.Reader, validW, invalidW io.Writer) (valid, invalid int, err error) {
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadString('\n')
+		if line != "" {
+			w := invalidW
+			if Validate(strings.TrimSpace(line)) == nil {
+				w = validW
+				valid++
+			} else {
+				invalid++
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return valid, invalid, err
+			}
+		}
+		if readErr == io.EOF {
+			return valid, invalid, nil
+		}
+		if readErr != nil {
+			return valid, invalid, readErr
+		}
+	}
+}
+
+// AgeHistogram reads one CNP per line from r and counts holders by their age at
+// at (see AgeAt), in buckets of bucketSize years keyed by their lower bound, so
+// with bucketSize 5 the key 10 counts ages 10–14. Lines are trimmed as in
+// ValidateReport. Blank lines, invalid CNPs and holders born after at are
+// skipped without being counted; a partial line, such as a last line cut off
+// mid-CNP, is simply invalid. Only the histogram is retained.
+// Returns an error if bucketSize is not positive or reading r fails, in which
+// case the counts so far are returned with it.
+func AgeHistogram(r io.Reader, at time.Time, bucketSize int) (map[int]int, error) {
+	if bucketSize <= 0 {
+		return nil, fmt.Errorf("invalid bucket size %d", bucketSize)
+	}
+	hist := make(map[int]int)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		age, err := AgeAt(strings.TrimSpace(sc.Text()), at)
+		if err != nil || age < 0 {
+			continue
+		}
+		hist[age/bucketSize*bucketSize]++
+	}
+	return hist, sc.Err()
+}
+
+// ValidateFixedWidth reads fixed-width records from r, one per line, and calls
+// fn for every line with its 1-based number, the trimmed bytes [start,
+// start+length) and the validation error. A line shorter than start+length is
+// reported with an empty CNP and ErrRecordTooShort. Line endings, including
+// "\r\n", are not part of the record. Returns an error if start or length is
+// negative or reading r fails.
+func ValidateFixedWidth(r io.Reader, start, length int, fn func(line int, cnp string, err error)) error {
+	if start < 0 || length < 0 {
+		return fmt.Errorf("invalid column range [%d, %d+%d)", start, start, length)
+	}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if start > len(text) || length > len(text)-start {
+			fn(line, "", ErrRecordTooShort)
+			continue
+		}
+		cnp := strings.TrimSpace(text[start : start+length])
+		fn(line, cnp, Validate(cnp))
+	}
+	return sc.Err()
+}
@@ -0,0 +1,181 @@
+// ABOUTME: Tests for streaming validation of newline-delimited input.
+package rossn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestValidateReport(t *testing.T) {
+	valid1 := buildCNP("1", "80", "01", "01", "01", "001")
+	valid2 := buildCNP("2", "95", "12", "15", "12", "123")
+	input := valid1 + "\n" +
+		"  " + valid2 + "\r\n" +
+		"\n" +
+		"123\n" +
+		"0000000000000"
+
+	rep, err := ValidateReport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ValidateReport returned error: %v", err)
+	}
+	if rep.Total != 4 || rep.Valid != 2 || rep.Invalid != 2 {
+		t.Errorf("Unexpected totals: %+v", rep)
+	}
+	if len(rep.Errors) != 2 {
+		t.Fatalf("Expected 2 line errors, got %d", len(rep.Errors))
+	}
+	if rep.Errors[0].Line != 4 || rep.Errors[0].CNP != "123" {
+		t.Errorf("Unexpected first error: %+v", rep.Errors[0])
+	}
+	if rep.Errors[1].Line != 5 || !errors.Is(rep.Errors[1], ErrPlaceholder) {
+		t.Errorf("Unexpected second error: %+v", rep.Errors[1])
+	}
+
+	out, err := json.Marshal(rep)
+	if err != nil {
+		t.Fatalf("Report should marshal to JSON: %v", err)
+	}
+	if !strings.Contains(string(out), `"line":4,"cnp":"123","error":"CNP must be 13 digits"`) {
+		t.Errorf("Unexpected JSON: %s", out)
+	}
+}
+
+func TestValidateReport_ErrorLimit(t *testing.T) {
+	input := strings.Repeat("bad\n", ReportErrorLimit+50)
+	rep, err := ValidateReport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ValidateReport returned error: %v", err)
+	}
+	if rep.Invalid != ReportErrorLimit+50 {
+		t.Errorf("All failures should be counted, got %d", rep.Invalid)
+	}
+	if len(rep.Errors) != ReportErrorLimit {
+		t.Errorf("Errors should be capped at %d, got %d", ReportErrorLimit, len(rep.Errors))
+	}
+}
+
+func TestPartition(t *testing.T) {
+	valid1 := buildCNP("1", "80", "01", "01", "01", "001")
+	valid2 := buildCNP("2", "95", "12", "15", "12", "123")
+	input := valid1 + "\r\n" +
+		"bad line\n" +
+		"\n" +
+		" " + valid2 + " \n" +
+		"0000000000000" // no trailing newline
+
+	var good, bad strings.Builder
+	v, inv, err := Partition(strings.NewReader(input), &good, &bad)
+	if err != nil {
+		t.Fatalf("Partition returned error: %v", err)
+	}
+	if v != 2 || inv != 3 {
+		t.Errorf("Partition counts = %d valid, %d invalid; want 2, 3", v, inv)
+	}
+	if want := valid1 + "\r\n" + " " + valid2 + " \n"; good.String() != want {
+		t.Errorf("Valid output = %q, want %q", good.String(), want)
+	}
+	if want := "bad line\n\n0000000000000"; bad.String() != want {
+		t.Errorf("Invalid output = %q, want %q", bad.String(), want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestPartition_WriteError(t *testing.T) {
+	input := buildCNP("1", "80", "01", "01", "01", "001") + "\n"
+	if _, _, err := Partition(strings.NewReader(input), failingWriter{}, &strings.Builder{}); err == nil {
+		t.Errorf("Partition should report write errors")
+	}
+}
+
+func TestAgeHistogram(t *testing.T) {
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	input := strings.Join([]string{
+		buildCNP("1", "85", "03", "17", "40", "123"),               // 40
+		buildCNP("2", "85", "06", "02", "40", "123"),               // 39
+		"  " + buildCNP("5", "20", "06", "01", "12", "001") + "\t", // 5
+		buildCNP("6", "24", "01", "01", "12", "001"),               // 1
+		buildCNP("5", "25", "06", "02", "12", "001"),               // born after at
+		"",
+		"not a cnp",
+		buildCNP("1", "85", "03", "17", "40", "123")[:7], // truncated
+	}, "\n")
+	hist, err := AgeHistogram(strings.NewReader(input), at, 5)
+	if err != nil {
+		t.Fatalf("AgeHistogram returned error: %v", err)
+	}
+	want := map[int]int{0: 1, 5: 1, 35: 1, 40: 1}
+	if fmt.Sprint(hist) != fmt.Sprint(want) {
+		t.Errorf("AgeHistogram = %v, want %v", hist, want)
+	}
+
+	hist, _ = AgeHistogram(strings.NewReader(input), at, 1)
+	if hist[39] != 1 || hist[40] != 1 || len(hist) != 4 {
+		t.Errorf("One-year buckets = %v", hist)
+	}
+	if _, err := AgeHistogram(strings.NewReader(input), at, 0); err == nil {
+		t.Errorf("A zero bucket size should fail")
+	}
+	if _, err := AgeHistogram(iotest.ErrReader(iotest.ErrTimeout), at, 5); err != iotest.ErrTimeout {
+		t.Errorf("Read errors should be returned, got %v", err)
+	}
+}
+
+func TestValidateFixedWidth(t *testing.T) {
+	a := buildCNP("1", "85", "03", "17", "40", "123")
+	input := "0001POPESCU   " + a + "RO\r\n" +
+		"0002IONESCU   " + " " + a[1:] + "RO\n" + // 12-digit CNP padded on the left
+		"0003SHORT\n" +
+		"0004ANA       " + a + "  \n" +
+		"\n"
+	type call struct {
+		line int
+		cnp  string
+		err  error
+	}
+	var got []call
+	err := ValidateFixedWidth(strings.NewReader(input), 14, 13, func(line int, cnp string, err error) {
+		got = append(got, call{line, cnp, err})
+	})
+	if err != nil {
+		t.Fatalf("ValidateFixedWidth returned error: %v", err)
+	}
+	want := []call{
+		{1, a, nil},
+		{2, a[1:], ErrInvalidLength},
+		{3, "", ErrRecordTooShort},
+		{4, a, nil},
+		{5, "", ErrRecordTooShort},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Got %d calls, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].line != want[i].line || got[i].cnp != want[i].cnp || !errors.Is(got[i].err, want[i].err) || (want[i].err == nil) != (got[i].err == nil) {
+			t.Errorf("Call %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A range past the end of the line must not overflow start+length.
+	var huge error
+	err = ValidateFixedWidth(strings.NewReader(a+"\n"), 1<<62, 1<<62, func(_ int, _ string, err error) { huge = err })
+	if err != nil || !errors.Is(huge, ErrRecordTooShort) {
+		t.Errorf("A huge column range = %v, %v; want ErrRecordTooShort", huge, err)
+	}
+
+	noop := func(int, string, error) {}
+	if err := ValidateFixedWidth(strings.NewReader(input), -1, 13, noop); err == nil {
+		t.Errorf("A negative start should fail")
+	}
+	if err := ValidateFixedWidth(iotest.ErrReader(iotest.ErrTimeout), 0, 13, noop); err != iotest.ErrTimeout {
+		t.Errorf("Read errors should be returned, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// ABOUTME: Tests for the byte-level ValidateStrict path.
+package rossn
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// ValidateStrict must return exactly what Validate returns for any input.
+func TestValidateStrict_MatchesValidate(t *testing.T) {
+	inputs := []string{"", "123", "12345678901234", "٠١٢٣٤٥٦٧٨٩", "1850317401231", "１850317401231"}
+	inputs = append(inputs, defaultPlaceholders...)
+	r := rand.New(rand.NewPCG(1, 2))
+	valid := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		valid = append(valid, generate(r.IntN))
+	}
+	valid = append(valid,
+		buildCNP("1", "75", "06", "15", "47", "001"), // archival
+		buildCNP("2", "79", "12", "18", "48", "001"), // last archival day
+		buildCNP("7", "85", "03", "17", "70", "123"),
+		buildCNP("5", "24", "03", "17", "70", "123"), // SIIEASC
+	)
+	for _, cnp := range valid {
+		inputs = append(inputs, cnp)
+		// Every single-byte change, including non-digits, exercises each check.
+		for i := 0; i < cnpLength; i++ {
+			for _, c := range "0123456789x " {
+				inputs = append(inputs, cnp[:i]+string(c)+cnp[i+1:])
+			}
+		}
+	}
+	// County and date sweeps around the archival cutoff and the SIIEASC start.
+	for s := '1'; s <= '9'; s++ {
+		for jj := 0; jj < 100; jj++ {
+			for _, date := range []string{"791218", "791219", "231231", "240101"} {
+				inputs = append(inputs, buildCNP(string(s), date[:2], date[2:4], date[4:], twoDigitString(jj), "001"))
+			}
+		}
+	}
+	for _, in := range inputs {
+		if got, want := ValidateStrict(in), Validate(in); got != want {
+			t.Errorf("ValidateStrict(%q) = %v, Validate = %v", in, got, want)
+		}
+	}
+}
+
+func twoDigitString(n int) string {
+	return string([]byte{byte('0' + n/10), byte('0' + n%10)})
+}
+
+func TestValidateStrict_NoAllocs(t *testing.T) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	if n := testing.AllocsPerRun(100, func() { _ = ValidateStrict(cnp) }); n != 0 {
+		t.Errorf("ValidateStrict allocated %v times per call", n)
+	}
+}
+
+// BenchmarkValidateStrict and BenchmarkValidate validate the same
+// CNP through the byte-level and the regular path.
+func BenchmarkValidateStrict(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		_ = ValidateStrict(cnp)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	cnp := buildCNP("2", "85", "03", "17", "40", "123")
+	for i := 0; i < b.N; i++ {
+		_ = Validate(cnp)
+	}
+}
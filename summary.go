@@ -0,0 +1,127 @@
+// ABOUTME: Single-pass demographic summary of a batch of CNPs.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"sort"
+	"time"
+)
+
+// SummaryTopCounties is the maximum number of counties listed in Summary.TopCounties.
+const SummaryTopCounties = 5
+
+// Summary aggregates a batch of CNPs for reporting. Invalid entries are counted
+// in Invalid and excluded from every other statistic.
+type Summary struct {
+	Total   int // entries in the batch
+	Valid   int
+	Invalid int
+
+	Male     int // valid CNPs by gender
+	Female   int
+	NoGender int // S=9, which encodes no gender
+
+	// Age statistics at the reference time. Holders born after it are left out,
+	// and with no holder left the fields are zero.
+	MeanAge   float64
+	MedianAge float64 // mean of the two middle ages for an even count
+
+	MinBirthYear int // century-aware; zero if there are no valid CNPs
+	MaxBirthYear int
+
+	TopCounties []CountyCount // most frequent counties, at most SummaryTopCounties
+}
+
+// CountyCount is the number of valid CNPs issued in a county.
+type CountyCount struct {
+	Code  string // two-digit JJ code
+	Name  string // county name
+	Count int
+}
+
+// Summarize computes a Summary of cnps with ages taken at at, validating each
+// entry once in a single pass. Memory does not grow with the batch: ages are
+// tallied per year of age and counties per code. TopCounties is ordered by
+// descending count, ties going to the lower code.
+func Summarize(cnps []string, at time.Time) Summary {
+	var sum Summary
+	var counties [100]int
+	ages := map[int]int{}
+	ageCount, ageTotal := 0, 0
+	for _, cnp := range cnps {
+		sum.Total++
+		c, err := Parse(cnp)
+		if err != nil {
+			sum.Invalid++
+			continue
+		}
+		sum.Valid++
+		switch c.Gender {
+		case Male:
+			sum.Male++
+		case Female:
+			sum.Female++
+		default:
+			sum.NoGender++
+		}
+		y, m, d := c.BirthDate.Date()
+		if sum.Valid == 1 || y < sum.MinBirthYear {
+			sum.MinBirthYear = y
+		}
+		if y > sum.MaxBirthYear {
+			sum.MaxBirthYear = y
+		}
+		if age := ageOn(y, int(m), d, at); age >= 0 {
+			ages[age]++
+			ageCount++
+			ageTotal += age
+		}
+		counties[(c.County[0]-'0')*10+c.County[1]-'0']++
+	}
+	if ageCount > 0 {
+		sum.MeanAge = float64(ageTotal) / float64(ageCount)
+		sum.MedianAge = medianAge(ages, ageCount)
+	}
+	sum.TopCounties = topCounties(counties)
+	return sum
+}
+
+// medianAge returns the median of n ages tallied by value in ages.
+func medianAge(ages map[int]int, n int) float64 {
+	values := make([]int, 0, len(ages))
+	for age := range ages {
+		values = append(values, age)
+	}
+	sort.Ints(values)
+	// nth returns the k-th smallest age, 0-based.
+	nth := func(k int) int {
+		for _, age := range values {
+			if k < ages[age] {
+				return age
+			}
+			k -= ages[age]
+		}
+		return values[len(values)-1]
+	}
+	if n%2 == 1 {
+		return float64(nth(n / 2))
+	}
+	return float64(nth(n/2-1)+nth(n/2)) / 2
+}
+
+// topCounties returns the most frequent counties in counts, indexed by code.
+func topCounties(counts [100]int) []CountyCount {
+	var top []CountyCount
+	for code, n := range counts {
+		if n > 0 {
+			jj := string([]byte{byte('0' + code/10), byte('0' + code%10)})
+			top = append(top, CountyCount{Code: jj, Name: countyNames[jj], Count: n})
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+	if len(top) > SummaryTopCounties {
+		top = top[:SummaryTopCounties]
+	}
+	return top
+}
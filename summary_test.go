@@ -0,0 +1,65 @@
+// ABOUTME: Tests for the single-pass batch summary.
+package rossn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cnps := []string{
+		buildCNP("1", "85", "03", "17", "40", "123"), // 40, M
+		buildCNP("2", "85", "06", "02", "40", "124"), // 39, F
+		buildCNP("6", "05", "01", "01", "12", "001"), // 20, F
+		buildCNP("9", "50", "01", "01", "70", "001"), // 75, no gender
+		buildCNP("3", "99", "12", "31", "01", "001"), // 125, M
+		buildCNP("5", "25", "12", "01", "12", "002"), // born after at, M
+		"123",
+		"0000000000000",
+	}
+	s := Summarize(cnps, at)
+	if s.Total != 8 || s.Valid != 6 || s.Invalid != 2 {
+		t.Errorf("Counts = %d/%d/%d, want 8/6/2", s.Total, s.Valid, s.Invalid)
+	}
+	if s.Male != 3 || s.Female != 2 || s.NoGender != 1 {
+		t.Errorf("Gender split = %d/%d/%d, want 3/2/1", s.Male, s.Female, s.NoGender)
+	}
+	// Ages 20, 39, 40, 75, 125; the 2025-12 birth is excluded.
+	if s.MeanAge != 59.8 || s.MedianAge != 40 {
+		t.Errorf("Mean/median age = %v/%v, want 59.8/40", s.MeanAge, s.MedianAge)
+	}
+	if s.MinBirthYear != 1899 || s.MaxBirthYear != 2025 {
+		t.Errorf("Birth years = %d–%d, want 1899–2025", s.MinBirthYear, s.MaxBirthYear)
+	}
+	want := []CountyCount{{"12", "Cluj", 2}, {"40", "București", 2}, {"01", "Alba", 1}, {"70", countyNames["70"], 1}}
+	if len(s.TopCounties) != len(want) {
+		t.Fatalf("TopCounties = %v, want %v", s.TopCounties, want)
+	}
+	for i := range want {
+		if s.TopCounties[i] != want[i] {
+			t.Errorf("TopCounties[%d] = %v, want %v", i, s.TopCounties[i], want[i])
+		}
+	}
+
+	// An even count takes the mean of the two middle ages.
+	even := Summarize(cnps[:2], at)
+	if even.MedianAge != 39.5 || even.MeanAge != 39.5 {
+		t.Errorf("Even median/mean = %v/%v, want 39.5/39.5", even.MedianAge, even.MeanAge)
+	}
+
+	// The list is capped, ties going to the lower code.
+	var many []string
+	for _, code := range []string{"09", "08", "07", "06", "05", "04", "03"} {
+		many = append(many, buildCNP("1", "85", "03", "17", code, "123"))
+	}
+	many = append(many, buildCNP("1", "85", "03", "17", "07", "124"))
+	top := Summarize(many, at).TopCounties
+	if len(top) != SummaryTopCounties || top[0].Code != "07" || top[1].Code != "03" || top[4].Code != "06" {
+		t.Errorf("Capped TopCounties = %v", top)
+	}
+
+	if empty := Summarize(nil, at); empty.Total != 0 || empty.MinBirthYear != 0 || empty.TopCounties != nil {
+		t.Errorf("Empty batch = %+v", empty)
+	}
+}
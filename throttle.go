@@ -0,0 +1,99 @@
+// ABOUTME: Optional per-key rate limiting around a Validator for public endpoints.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by ThrottledValidator when a key has exhausted its quota.
+var ErrRateLimited = errors.New("CNP validation rate limit exceeded")
+
+// ThrottledValidator wraps a Validator with a token bucket per key (for example a
+// client IP or API key), so a validation endpoint cannot be used to enumerate
+// valid CNPs. Refills are timed by the Validator's Clock (see WithClock). It
+// uses only the standard library and is safe for concurrent use.
+type ThrottledValidator struct {
+	v     *Validator
+	rate  float64 // tokens added per second
+	burst float64 // bucket capacity
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottledValidator allows each key perSecond validations per second on
+// average, with bursts of up to burst validations.
+func NewThrottledValidator(v *Validator, perSecond float64, burst int) *ThrottledValidator {
+	return &ThrottledValidator{
+		v:       v,
+		rate:    perSecond,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Validate checks the CNP on behalf of key. It returns ErrRateLimited without
+// validating if key has no quota left; otherwise it returns the Validator's result.
+func (t *ThrottledValidator) Validate(key, cnp string) error {
+	if !t.allow(key) {
+		return ErrRateLimited
+	}
+	return t.v.Validate(cnp)
+}
+
+// allow takes a token from key's bucket, refilling it for the time elapsed.
+func (t *ThrottledValidator) allow(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.v.cfg.clock.Now()
+	t.sweep(now)
+	b, ok := t.buckets[key]
+	if !ok {
+		b = &bucket{tokens: t.burst, last: now}
+		t.buckets[key] = b
+	}
+	b.tokens = t.refill(b, now)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill returns the bucket's token count after the time elapsed since its last use.
+func (t *ThrottledValidator) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*t.rate
+	if tokens > t.burst {
+		tokens = t.burst
+	}
+	return tokens
+}
+
+// sweep drops buckets that have refilled completely, since a fresh bucket is
+// equivalent. It runs at most once per full-refill period to bound its cost.
+func (t *ThrottledValidator) sweep(now time.Time) {
+	if t.rate <= 0 {
+		return
+	}
+	period := time.Duration(t.burst / t.rate * float64(time.Second))
+	if now.Sub(t.lastSweep) < period {
+		return
+	}
+	t.lastSweep = now
+	for key, b := range t.buckets {
+		if t.refill(b, now) >= t.burst {
+			delete(t.buckets, key)
+		}
+	}
+}
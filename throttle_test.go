@@ -0,0 +1,62 @@
+// ABOUTME: Tests for the rate-limited validator wrapper.
+package rossn
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// clockFunc adapts a function to Clock, for clocks a test moves by hand.
+type clockFunc func() time.Time
+
+func (f clockFunc) Now() time.Time { return f() }
+
+func TestThrottledValidator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tv := NewThrottledValidator(NewValidator(WithClock(clockFunc(func() time.Time { return now }))), 1, 3)
+	cnp := buildCNP("1", "80", "01", "01", "01", "001")
+
+	for i := 0; i < 3; i++ {
+		if err := tv.Validate("alice", cnp); err != nil {
+			t.Fatalf("Call %d within burst should pass, got %v", i, err)
+		}
+	}
+	if err := tv.Validate("alice", cnp); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Call beyond burst should be rate limited, got %v", err)
+	}
+
+	// Quotas are per key.
+	if err := tv.Validate("bob", cnp); err != nil {
+		t.Errorf("Another key should have its own quota, got %v", err)
+	}
+
+	// One token is restored per second.
+	now = now.Add(time.Second)
+	if err := tv.Validate("alice", cnp); err != nil {
+		t.Errorf("Call after refill should pass, got %v", err)
+	}
+	if err := tv.Validate("alice", cnp); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Only one token should have been restored, got %v", err)
+	}
+
+	// Validation errors are passed through when quota remains.
+	if err := tv.Validate("carol", "123"); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestThrottledValidator_Sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tv := NewThrottledValidator(NewValidator(WithClock(clockFunc(func() time.Time { return now }))), 10, 10)
+	cnp := Generate()
+
+	for _, key := range []string{"a", "b", "c"} {
+		tv.Validate(key, cnp)
+	}
+	now = now.Add(time.Minute)
+	tv.Validate("d", cnp)
+	if len(tv.buckets) != 1 {
+		t.Errorf("Refilled buckets should be swept, %d remain", len(tv.buckets))
+	}
+}
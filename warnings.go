@@ -0,0 +1,61 @@
+// ABOUTME: Validation that reports soft issues as warnings alongside hard failures.
+// MIT License – see LICENSE file.
+
+package rossn
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Stable codes for the soft issues reported by ValidateWithWarnings.
+const (
+	WarningArchivalCounty = "archival_county" // 47/48 after the 1979-12-19 cutoff, accepted via WarnArchival
+	WarningSuspiciousDate = "suspicious_date" // a commonly fabricated birth date such as 1970-01-01
+	WarningNoGender       = "no_gender"       // S=9 encodes no gender
+	WarningSerialTooHigh  = "serial_too_high" // serial above WithMaxIssuedSerial's maximum, in warning mode
+)
+
+// Warning is a soft issue with a CNP that passed validation.
+type Warning struct {
+	Code    string // one of the Warning* constants
+	Message string // human-readable description
+}
+
+// ValidateWithWarnings validates the CNP like ValidateWith and, if it passes,
+// also returns the soft issues that did not fail it under opts: a historic
+// county accepted through WarnArchival (whose callback still runs), a birth date
+// that RejectSuspiciousDates would reject by default, an S=9 CNP without a
+// gender, and a serial above the maximum of WithMaxIssuedSerial in its
+// warning-only mode. err is the validation failure, in which case warnings is nil.
+func ValidateWithWarnings(cnp string, opts ...Option) (warnings []Warning, err error) {
+	c := newConfig(opts)
+	archival := false
+	if fn := c.warnArchival; fn != nil {
+		c.warnArchival = func(cnp string) {
+			archival = true
+			fn(cnp)
+		}
+	}
+	if err := c.validate(cnp); err != nil {
+		return nil, err
+	}
+	if archival {
+		warnings = append(warnings, Warning{WarningArchivalCounty, "historic Bucharest county code used after 1979-12-19"})
+	}
+	year, month, day := cnpBirthDate(cnp)
+	for _, d := range defaultSuspiciousDates {
+		if d.Equal(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)) {
+			warnings = append(warnings, Warning{WarningSuspiciousDate, "birth date " + d.Format("2006-01-02") + " is commonly fabricated"})
+		}
+	}
+	f := fields(cnp)
+	if genderOf(f.s) == "" {
+		warnings = append(warnings, Warning{WarningNoGender, "non-resident CNP encodes no gender"})
+	}
+	if serial, _ := strconv.Atoi(f.nnn); c.serialTooHigh(serial) {
+		warnings = append(warnings, Warning{WarningSerialTooHigh, fmt.Sprintf("serial %03d is above the maximum issued %d", serial, c.maxSerial)})
+	}
+	return warnings, nil
+}
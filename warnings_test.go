@@ -0,0 +1,56 @@
+// ABOUTME: Tests for validation warnings.
+package rossn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateWithWarnings(t *testing.T) {
+	codes := func(ws []Warning) string {
+		s := ""
+		for _, w := range ws {
+			if w.Message == "" {
+				t.Errorf("Warning %s has no message", w.Code)
+			}
+			s += w.Code + ";"
+		}
+		return s
+	}
+
+	lateArchival := buildCNP("1", "85", "03", "10", "47", "123")
+	called := false
+	cases := []struct {
+		cnp  string
+		opts []Option
+		want string
+	}{
+		{buildCNP("1", "80", "01", "02", "01", "001"), nil, ""},
+		{buildCNP("1", "70", "01", "01", "01", "001"), nil, WarningSuspiciousDate + ";"},
+		{buildCNP("9", "00", "01", "01", "70", "555"), nil, WarningSuspiciousDate + ";" + WarningNoGender + ";"},
+		{buildCNP("9", "85", "05", "01", "70", "555"), nil, WarningNoGender + ";"},
+		{lateArchival, []Option{WarnArchival(func(string) { called = true })}, WarningArchivalCounty + ";"},
+	}
+	for _, tc := range cases {
+		ws, err := ValidateWithWarnings(tc.cnp, tc.opts...)
+		if err != nil {
+			t.Errorf("ValidateWithWarnings(%s) returned error: %v", tc.cnp, err)
+			continue
+		}
+		if got := codes(ws); got != tc.want {
+			t.Errorf("ValidateWithWarnings(%s) warnings = %q, want %q", tc.cnp, got, tc.want)
+		}
+	}
+	if !called {
+		t.Errorf("The WarnArchival callback should still run")
+	}
+
+	// Hard failures under the active options are errors, not warnings.
+	if ws, err := ValidateWithWarnings(lateArchival); !errors.Is(err, ErrInvalidCounty) || ws != nil {
+		t.Errorf("Late archival without WarnArchival should fail, got %v, %v", ws, err)
+	}
+	fake := buildCNP("1", "70", "01", "01", "01", "001")
+	if ws, err := ValidateWithWarnings(fake, RejectSuspiciousDates()); !errors.Is(err, ErrSuspiciousDate) || ws != nil {
+		t.Errorf("Rejected suspicious date should be an error, got %v, %v", ws, err)
+	}
+}